@@ -1,8 +1,12 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
+	"runtime"
+	"strings"
+	"time"
 )
 
 // LegacyLogger is the old logger interface, kept for backward compatibility
@@ -19,61 +23,250 @@ type LegacyLogger interface {
 	With(key string, value any) LegacyLogger
 }
 
+// LegacyLoggerExt extends LegacyLogger with fatal, panic, printf and
+// key-value methods. The LegacyLogger returned by NewLegacyAdapter and by
+// the With method of a LegacyAdapter implements it.
+type LegacyLoggerExt interface {
+	LegacyLogger
+
+	// Fatal logs a message and exits with status 1
+	Fatal(args ...any)
+	// Panic logs a message and panics with it
+	Panic(args ...any)
+
+	// Debugf logs a formatted debug message
+	Debugf(format string, args ...any)
+	// Infof logs a formatted info message
+	Infof(format string, args ...any)
+	// Warnf logs a formatted warning message
+	Warnf(format string, args ...any)
+	// Errorf logs a formatted error message
+	Errorf(format string, args ...any)
+	// Fatalf logs a formatted message and exits with status 1
+	Fatalf(format string, args ...any)
+	// Panicf logs a formatted message and panics with it
+	Panicf(format string, args ...any)
+
+	// Debugw logs a debug message with key-value pairs
+	Debugw(msg string, keysAndValues ...any)
+	// Infow logs an info message with key-value pairs
+	Infow(msg string, keysAndValues ...any)
+	// Warnw logs a warning message with key-value pairs
+	Warnw(msg string, keysAndValues ...any)
+	// Errorw logs an error message with key-value pairs
+	Errorw(msg string, keysAndValues ...any)
+	// Fatalw logs a message with key-value pairs and exits with status 1
+	Fatalw(msg string, keysAndValues ...any)
+	// Panicw logs a message with key-value pairs and panics with the message
+	Panicw(msg string, keysAndValues ...any)
+}
+
+// LegacyOptions configures a LegacyAdapter
+type LegacyOptions struct {
+	// SprintArgs formats all arguments of Debug, Info, Warn, Error, Fatal and
+	// Panic into the message, separated by spaces, instead of treating the
+	// arguments after the first as key-value pairs
+	SprintArgs bool
+
+	// Flusher is flushed before Fatal, Fatalf and Fatalw exit, such as the
+	// *Logger returned by Open. Defaults to the handler of the logger if it
+	// implements Flusher.
+	Flusher Flusher
+}
+
 // LegacyAdapter adapts a *slog.Logger to the LegacyLogger interface
 type LegacyAdapter struct {
 	logger *slog.Logger
+	opts   LegacyOptions
 }
 
+var _ LegacyLoggerExt = (*LegacyAdapter)(nil)
+
 // NewLegacyAdapter creates a new LegacyAdapter
 func NewLegacyAdapter(logger *slog.Logger) LegacyLogger {
 	return &LegacyAdapter{logger: logger}
 }
 
-// Debug logs a debug message
-func (l *LegacyAdapter) Debug(args ...any) {
-	if len(args) == 0 {
-		return
+// NewLegacyAdapterWithOptions creates a new LegacyAdapter with the given options
+func NewLegacyAdapterWithOptions(logger *slog.Logger, opts *LegacyOptions) *LegacyAdapter {
+	if opts == nil {
+		opts = &LegacyOptions{}
 	}
+	return &LegacyAdapter{logger: logger, opts: *opts}
+}
 
-	msg, attrs := extractMsgAndAttrs(args)
-	l.logger.Debug(msg, attrs...)
+// Debug logs a debug message
+func (l *LegacyAdapter) Debug(args ...any) {
+	l.logArgs(slog.LevelDebug, args)
 }
 
 // Info logs an info message
 func (l *LegacyAdapter) Info(args ...any) {
-	if len(args) == 0 {
-		return
-	}
-
-	msg, attrs := extractMsgAndAttrs(args)
-	l.logger.Info(msg, attrs...)
+	l.logArgs(slog.LevelInfo, args)
 }
 
 // Warn logs a warning message
 func (l *LegacyAdapter) Warn(args ...any) {
-	if len(args) == 0 {
-		return
-	}
-
-	msg, attrs := extractMsgAndAttrs(args)
-	l.logger.Warn(msg, attrs...)
+	l.logArgs(slog.LevelWarn, args)
 }
 
 // Error logs an error message
 func (l *LegacyAdapter) Error(args ...any) {
-	if len(args) == 0 {
-		return
-	}
+	l.logArgs(slog.LevelError, args)
+}
+
+// Fatal logs a message at LevelFatal, flushes the adapter and the default logger and exits with status 1
+func (l *LegacyAdapter) Fatal(args ...any) {
+	l.logArgs(LevelFatal, args)
+	l.exit()
+}
+
+// Panic logs a message at LevelPanic and panics with it
+func (l *LegacyAdapter) Panic(args ...any) {
+	msg := l.logArgs(LevelPanic, args)
+	panic(msg)
+}
+
+// Debugf logs a formatted debug message
+func (l *LegacyAdapter) Debugf(format string, args ...any) {
+	l.logf(slog.LevelDebug, format, args)
+}
+
+// Infof logs a formatted info message
+func (l *LegacyAdapter) Infof(format string, args ...any) {
+	l.logf(slog.LevelInfo, format, args)
+}
+
+// Warnf logs a formatted warning message
+func (l *LegacyAdapter) Warnf(format string, args ...any) {
+	l.logf(slog.LevelWarn, format, args)
+}
+
+// Errorf logs a formatted error message
+func (l *LegacyAdapter) Errorf(format string, args ...any) {
+	l.logf(slog.LevelError, format, args)
+}
 
-	msg, attrs := extractMsgAndAttrs(args)
-	l.logger.Error(msg, attrs...)
+// Fatalf logs a formatted message at LevelFatal, flushes the adapter and the default logger and exits with status 1
+func (l *LegacyAdapter) Fatalf(format string, args ...any) {
+	l.logf(LevelFatal, format, args)
+	l.exit()
+}
+
+// Panicf logs a formatted message at LevelPanic and panics with it
+func (l *LegacyAdapter) Panicf(format string, args ...any) {
+	l.logf(LevelPanic, format, args)
+	panic(fmt.Sprintf(format, args...))
+}
+
+// Debugw logs a debug message with key-value pairs
+func (l *LegacyAdapter) Debugw(msg string, keysAndValues ...any) {
+	l.logw(slog.LevelDebug, msg, keysAndValues)
+}
+
+// Infow logs an info message with key-value pairs
+func (l *LegacyAdapter) Infow(msg string, keysAndValues ...any) {
+	l.logw(slog.LevelInfo, msg, keysAndValues)
+}
+
+// Warnw logs a warning message with key-value pairs
+func (l *LegacyAdapter) Warnw(msg string, keysAndValues ...any) {
+	l.logw(slog.LevelWarn, msg, keysAndValues)
+}
+
+// Errorw logs an error message with key-value pairs
+func (l *LegacyAdapter) Errorw(msg string, keysAndValues ...any) {
+	l.logw(slog.LevelError, msg, keysAndValues)
+}
+
+// Fatalw logs a message with key-value pairs at LevelFatal, flushes the adapter and the default logger and exits with status 1
+func (l *LegacyAdapter) Fatalw(msg string, keysAndValues ...any) {
+	l.logw(LevelFatal, msg, keysAndValues)
+	l.exit()
+}
+
+// Panicw logs a message with key-value pairs at LevelPanic and panics with the message
+func (l *LegacyAdapter) Panicw(msg string, keysAndValues ...any) {
+	l.logw(LevelPanic, msg, keysAndValues)
+	panic(msg)
 }
 
 // With adds structured context to the logger
 func (l *LegacyAdapter) With(key string, value any) LegacyLogger {
 	return &LegacyAdapter{
 		logger: l.logger.With(key, value),
+		opts:   l.opts,
+	}
+}
+
+// exit flushes the logger of the adapter, which may not be the default
+// logger, then shuts down the default logger and exits with status 1
+func (l *LegacyAdapter) exit() {
+	flusher := l.opts.Flusher
+	if flusher == nil {
+		flusher, _ = l.logger.Handler().(Flusher)
+	}
+	if flusher != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), exitShutdownTimeout)
+		_ = flusher.Flush(ctx)
+		cancel()
+	}
+
+	shutdownAndExit(context.Background(), 1)
+}
+
+// logArgs logs args as a message followed by key-value pairs, or as one
+// message in SprintArgs mode, and returns the message
+func (l *LegacyAdapter) logArgs(level slog.Level, args []any) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	var msg string
+	var attrs []any
+	if l.opts.SprintArgs {
+		msg = sprintArgs(args)
+	} else {
+		msg, attrs = extractMsgAndAttrs(args)
+	}
+
+	l.write(level, msg, attrs)
+	return msg
+}
+
+// logf logs a formatted message
+func (l *LegacyAdapter) logf(level slog.Level, format string, args []any) {
+	if !l.logger.Enabled(context.Background(), level) {
+		return
 	}
+	l.write(level, fmt.Sprintf(format, args...), nil)
+}
+
+// logw logs a message with key-value pairs
+func (l *LegacyAdapter) logw(level slog.Level, msg string, keysAndValues []any) {
+	l.write(level, msg, keysAndValues)
+}
+
+// write logs a record attributed to the caller of the exported method.
+// It must be called by a helper called directly by that method.
+func (l *LegacyAdapter) write(level slog.Level, msg string, attrs []any) {
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	// Skip runtime.Callers, write, the helper and the exported method
+	var pcs [1]uintptr
+	runtime.Callers(4, pcs[:])
+
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.Add(attrs...)
+	_ = l.logger.Handler().Handle(ctx, r)
+}
+
+// sprintArgs formats args like fmt.Sprintln, without the newline
+func sprintArgs(args []any) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
 }
 
 // extractMsgAndAttrs extracts the message and attributes from args
@@ -1,9 +1,13 @@
 package logger
 
 import (
+	"bytes"
+	"context"
+	"log/slog"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExtractMsgAndAttrsFunction(t *testing.T) {
@@ -53,3 +57,141 @@ func TestExtractMsgAndAttrsFunction(t *testing.T) {
 		})
 	}
 }
+
+func TestLegacyAdapterFormatted(t *testing.T) {
+	mock := &MockLogger{}
+	l := NewLegacyAdapterWithOptions(slog.New(mock), nil)
+
+	l.Infof("user %s logged in %d times", "alice", 3)
+	l.Errorw("request failed", "status", 500, "path", "/api")
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 2)
+	assert.Equal(t, slog.LevelInfo, logs[0].Level)
+	assert.Equal(t, "user alice logged in 3 times", logs[0].Message)
+	assert.Empty(t, logs[0].Attrs)
+	assert.Equal(t, slog.LevelError, logs[1].Level)
+	assert.Equal(t, "request failed", logs[1].Message)
+	assert.Equal(t, []slog.Attr{slog.Int("status", 500), slog.String("path", "/api")}, logs[1].Attrs)
+}
+
+func TestLegacyAdapterWith(t *testing.T) {
+	mock := &MockLogger{}
+	l, ok := NewLegacyAdapter(slog.New(mock)).With("service", "api").(LegacyLoggerExt)
+	require.True(t, ok)
+
+	l.Infof("started on %d", 8080)
+	l.With("id", 1).(LegacyLoggerExt).Warnw("slow", "ms", 1200)
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 2)
+	assert.Equal(t, "started on 8080", logs[0].Message)
+	assert.Equal(t, []slog.Attr{slog.String("service", "api")}, logs[0].Attrs)
+	assert.Equal(t, []slog.Attr{slog.String("service", "api"), slog.Int("id", 1), slog.Int("ms", 1200)}, logs[1].Attrs)
+}
+
+func TestLegacyAdapterSprintArgs(t *testing.T) {
+	mock := &MockLogger{}
+	l := NewLegacyAdapterWithOptions(slog.New(mock), &LegacyOptions{SprintArgs: true})
+
+	l.Warn("retrying in", 5, "seconds")
+
+	logs := mock.GetLogs()
+	require.NotEmpty(t, logs)
+	assert.Equal(t, "retrying in 5 seconds", logs[0].Message)
+	assert.Empty(t, logs[0].Attrs)
+}
+
+func TestLegacyAdapterSource(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLegacyAdapterWithOptions(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true})), nil)
+
+	l.Info("plain")
+	l.Infof("formatted")
+	l.Infow("key-value")
+
+	records := decodeRecords(t, &buf)
+	require.Len(t, records, 3)
+	for _, record := range records {
+		source, ok := record["source"].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "github.com/legrch/logger.TestLegacyAdapterSource", source["function"], record["msg"])
+	}
+}
+
+func TestLegacyAdapterDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLegacyAdapterWithOptions(slog.New(slog.NewJSONHandler(&buf, nil)), nil)
+
+	l.Debugf("hidden %d", 1)
+	l.Debugw("hidden")
+	l.Debug("hidden")
+
+	assert.Empty(t, buf.String())
+}
+
+func TestLegacyAdapterFatal(t *testing.T) {
+	var codes []int
+	prev := exit
+	exit = func(c int) { codes = append(codes, c) }
+	t.Cleanup(func() { exit = prev })
+
+	mock := &MockLogger{}
+	l := NewLegacyAdapterWithOptions(slog.New(mock), nil)
+
+	l.Fatal("config missing", "path", "/etc/app.yaml")
+	l.Fatalf("bad port %d", 0)
+	l.Fatalw("unreachable", "host", "db")
+
+	assert.Equal(t, []int{1, 1, 1}, codes)
+	logs := mock.GetLogs()
+	require.Len(t, logs, 3)
+	for _, entry := range logs {
+		assert.Equal(t, LevelFatal, entry.Level)
+	}
+	assert.Equal(t, "bad port 0", logs[1].Message)
+}
+
+// countingFlusher counts the calls to Flush
+type countingFlusher struct {
+	flushes int
+}
+
+func (f *countingFlusher) Flush(context.Context) error {
+	f.flushes++
+	return nil
+}
+
+func TestLegacyAdapterFatalFlush(t *testing.T) {
+	prev := exit
+	exit = func(int) {}
+	t.Cleanup(func() { exit = prev })
+
+	flusher := &countingFlusher{}
+	l := NewLegacyAdapterWithOptions(slog.New(&MockLogger{}), &LegacyOptions{Flusher: flusher})
+	l.With("k", "v").(LegacyLoggerExt).Fatal("exiting")
+	assert.Equal(t, 1, flusher.flushes)
+
+	// The handler of the logger is flushed by default
+	mock := &MockLogger{}
+	async := NewAsyncHandler(mock, nil)
+	t.Cleanup(func() { _ = async.Close(context.Background()) })
+	NewLegacyAdapter(slog.New(async)).(LegacyLoggerExt).Fatalf("exiting %d", 1)
+	assert.Len(t, mock.GetLogs(), 1)
+}
+
+func TestLegacyAdapterPanic(t *testing.T) {
+	mock := &MockLogger{}
+	l := NewLegacyAdapterWithOptions(slog.New(mock), &LegacyOptions{SprintArgs: true})
+
+	assert.PanicsWithValue(t, "lost 3 rows", func() { l.Panic("lost", 3, "rows") })
+	assert.PanicsWithValue(t, "code 7", func() { l.Panicf("code %d", 7) })
+	assert.PanicsWithValue(t, "broken", func() { l.Panicw("broken", "id", 1) })
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 3)
+	for _, entry := range logs {
+		assert.Equal(t, LevelPanic, entry.Level)
+	}
+	assert.Equal(t, []slog.Attr{slog.Int("id", 1)}, logs[2].Attrs)
+}
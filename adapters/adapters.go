@@ -0,0 +1,63 @@
+// Package adapters implements the logger interfaces of common libraries on
+// top of a *slog.Logger, so their output goes through the handler chain
+// configured by logger.New or logger.Open:
+//
+//	hclog:    adapters.NewHCLogger(l, nil)
+//	pgx:      &tracelog.TraceLog{Logger: adapters.NewPgxLogger(l), LogLevel: tracelog.LogLevelInfo}
+//	go-redis: redis.SetLogger(adapters.NewRedisLogger(l, slog.LevelWarn))
+//	gorm:     gorm.Open(dialector, &gorm.Config{Logger: adapters.NewGormLogger(l, nil)})
+//	sarama:   sarama.Logger = adapters.NewSaramaLogger(l, slog.LevelInfo)
+//	franz-go: kgo.WithLogger(adapters.NewKgoLogger[kgo.LogLevel](l))
+//
+// The sarama and franz-go adapters satisfy the interfaces of those libraries
+// without importing them.
+package adapters
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// maxCallerDepth bounds the stack searched for the caller of a library
+const maxCallerDepth = 32
+
+// write handles a record with the given caller, skipping disabled levels
+func write(ctx context.Context, l *slog.Logger, level slog.Level, msg string, pc uintptr, attrs ...slog.Attr) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !l.Enabled(ctx, level) {
+		return
+	}
+
+	r := slog.NewRecord(time.Now(), level, msg, pc)
+	r.AddAttrs(attrs...)
+	_ = l.Handler().Handle(ctx, r)
+}
+
+// libraryCaller returns the program counter of the function that called
+// into the library whose functions start with prefix, found as the first
+// frame after the library frames, or 0 if the library is not on the stack
+func libraryCaller(prefix string) uintptr {
+	var pcs [maxCallerDepth]uintptr
+	n := runtime.Callers(3, pcs[:])
+
+	inLibrary := false
+	for _, pc := range pcs[:n] {
+		fn := runtime.FuncForPC(pc - 1)
+		if fn == nil {
+			continue
+		}
+		if strings.HasPrefix(fn.Name(), prefix) {
+			inLibrary = true
+			continue
+		}
+		if inLibrary {
+			return pc
+		}
+	}
+	return 0
+}
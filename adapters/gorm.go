@@ -0,0 +1,158 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/legrch/logger"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// gormPrefix is the prefix of the functions of gorm
+const gormPrefix = "gorm.io/gorm"
+
+// Query attribute keys
+const (
+	SQLKey           = "sql"
+	RowsKey          = "rows"
+	ElapsedKey       = "elapsed"
+	SlowThresholdKey = "slow_threshold"
+)
+
+// DefaultSlowThreshold is the default duration above which gorm queries are logged as slow
+const DefaultSlowThreshold = 200 * time.Millisecond
+
+// GormOptions configures a GormLogger
+type GormOptions struct {
+	// LogLevel is the gorm log level, defaults to gormlogger.Warn
+	LogLevel gormlogger.LogLevel
+
+	// SlowThreshold is the duration above which queries are logged at warn,
+	// defaults to DefaultSlowThreshold. A negative value disables it.
+	SlowThreshold time.Duration
+
+	// IgnoreRecordNotFoundError does not log gormlogger.ErrRecordNotFound errors
+	IgnoreRecordNotFoundError bool
+
+	// ParameterizedQueries logs queries without their parameters
+	ParameterizedQueries bool
+}
+
+// GormLogger implements gormlogger.Interface. Failed queries are logged at
+// error, slow queries at warn and, at gormlogger.Info, all queries at info.
+type GormLogger struct {
+	logger *slog.Logger
+	opts   GormOptions
+}
+
+var (
+	_ gormlogger.Interface = (*GormLogger)(nil)
+	_ gorm.ParamsFilter    = (*GormLogger)(nil)
+)
+
+// NewGormLogger creates a GormLogger writing to l
+func NewGormLogger(l *slog.Logger, opts *GormOptions) *GormLogger {
+	if opts == nil {
+		opts = &GormOptions{}
+	}
+
+	o := *opts
+	if o.LogLevel == 0 {
+		o.LogLevel = gormlogger.Warn
+	}
+	if o.SlowThreshold == 0 {
+		o.SlowThreshold = DefaultSlowThreshold
+	}
+
+	return &GormLogger{logger: l, opts: o}
+}
+
+// LogMode implements gormlogger.Interface.
+func (g *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	g2 := *g
+	g2.opts.LogLevel = level
+	return &g2
+}
+
+// Info implements gormlogger.Interface.
+func (g *GormLogger) Info(ctx context.Context, msg string, data ...any) {
+	g.printf(ctx, gormlogger.Info, slog.LevelInfo, msg, data)
+}
+
+// Warn implements gormlogger.Interface.
+func (g *GormLogger) Warn(ctx context.Context, msg string, data ...any) {
+	g.printf(ctx, gormlogger.Warn, slog.LevelWarn, msg, data)
+}
+
+// Error implements gormlogger.Interface.
+func (g *GormLogger) Error(ctx context.Context, msg string, data ...any) {
+	g.printf(ctx, gormlogger.Error, slog.LevelError, msg, data)
+}
+
+// Trace implements gormlogger.Interface.
+func (g *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if g.opts.LogLevel <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	slow := g.opts.SlowThreshold > 0 && elapsed > g.opts.SlowThreshold
+
+	var (
+		level slog.Level
+		msg   string
+	)
+	switch {
+	case err != nil && g.opts.LogLevel >= gormlogger.Error &&
+		(!errors.Is(err, gormlogger.ErrRecordNotFound) || !g.opts.IgnoreRecordNotFoundError):
+		level, msg = slog.LevelError, "query failed"
+	case slow && g.opts.LogLevel >= gormlogger.Warn:
+		level, msg = slog.LevelWarn, "slow query"
+	case g.opts.LogLevel == gormlogger.Info:
+		level, msg = slog.LevelInfo, "query"
+	default:
+		return
+	}
+
+	if !g.logger.Enabled(ctx, level) {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []slog.Attr{
+		slog.String(SQLKey, sql),
+		slog.Duration(ElapsedKey, elapsed),
+	}
+	if rows >= 0 {
+		attrs = append(attrs, slog.Int64(RowsKey, rows))
+	}
+	if slow {
+		attrs = append(attrs, slog.Duration(SlowThresholdKey, g.opts.SlowThreshold))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Attr{Key: logger.ErrorKey, Value: logger.ErrValue(err)})
+	}
+
+	write(ctx, g.logger, level, msg, libraryCaller(gormPrefix), attrs...)
+}
+
+// ParamsFilter implements gorm.ParamsFilter, dropping the parameters
+// of queries with ParameterizedQueries
+func (g *GormLogger) ParamsFilter(_ context.Context, sql string, params ...any) (string, []any) {
+	if g.opts.ParameterizedQueries {
+		return sql, nil
+	}
+	return sql, params
+}
+
+// printf logs a message formatted like the gorm logger if the gorm level allows it
+func (g *GormLogger) printf(ctx context.Context, threshold gormlogger.LogLevel, level slog.Level, msg string, data []any) {
+	if g.opts.LogLevel < threshold || !g.logger.Enabled(ctx, level) {
+		return
+	}
+	write(ctx, g.logger, level, fmt.Sprintf(msg, data...), libraryCaller(gormPrefix))
+}
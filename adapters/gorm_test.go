@@ -0,0 +1,79 @@
+package adapters
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/legrch/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func attrMap(attrs []slog.Attr) map[string]slog.Value {
+	m := make(map[string]slog.Value, len(attrs))
+	for _, attr := range attrs {
+		m[attr.Key] = attr.Value
+	}
+	return m
+}
+
+func TestGormLoggerTrace(t *testing.T) {
+	mock := &logger.MockLogger{}
+	gl := NewGormLogger(slog.New(mock), &GormOptions{SlowThreshold: 50 * time.Millisecond})
+	ctx := context.Background()
+	query := func() (string, int64) { return "SELECT * FROM users", 3 }
+
+	gl.Trace(ctx, time.Now(), query, nil)
+	gl.Trace(ctx, time.Now().Add(-time.Second), query, nil)
+	gl.Trace(ctx, time.Now(), func() (string, int64) { return "SELECT 1", -1 }, gormlogger.ErrRecordNotFound)
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 2)
+
+	assert.Equal(t, slog.LevelWarn, logs[0].Level)
+	assert.Equal(t, "slow query", logs[0].Message)
+	slow := attrMap(logs[0].Attrs)
+	assert.Equal(t, "SELECT * FROM users", slow[SQLKey].String())
+	assert.Equal(t, int64(3), slow[RowsKey].Int64())
+	assert.Equal(t, 50*time.Millisecond, slow[SlowThresholdKey].Duration())
+	assert.GreaterOrEqual(t, slow[ElapsedKey].Duration(), time.Second)
+
+	assert.Equal(t, slog.LevelError, logs[1].Level)
+	failed := attrMap(logs[1].Attrs)
+	assert.NotContains(t, failed, RowsKey)
+	assert.Contains(t, failed, logger.ErrorKey)
+}
+
+func TestGormLoggerModes(t *testing.T) {
+	mock := &logger.MockLogger{}
+	gl := NewGormLogger(slog.New(mock), &GormOptions{IgnoreRecordNotFoundError: true})
+	ctx := context.Background()
+	query := func() (string, int64) { return "SELECT 1", 1 }
+
+	gl.Trace(ctx, time.Now(), query, gormlogger.ErrRecordNotFound)
+	gl.Info(ctx, "hidden %d", 1)
+	gl.Warn(ctx, "pool %s", "exhausted")
+
+	verbose := gl.LogMode(gormlogger.Info)
+	verbose.Trace(ctx, time.Now(), query, nil)
+	verbose.Info(ctx, "migrated %d tables", 2)
+
+	gl.LogMode(gormlogger.Silent).Error(ctx, "hidden")
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 3)
+	assert.Equal(t, "pool exhausted", logs[0].Message)
+	assert.Equal(t, slog.LevelInfo, logs[1].Level)
+	assert.Equal(t, "query", logs[1].Message)
+	assert.Equal(t, "migrated 2 tables", logs[2].Message)
+}
+
+func TestGormLoggerParamsFilter(t *testing.T) {
+	gl := NewGormLogger(slog.New(&logger.MockLogger{}), &GormOptions{ParameterizedQueries: true})
+	sql, params := gl.ParamsFilter(context.Background(), "SELECT ?", 1)
+	assert.Equal(t, "SELECT ?", sql)
+	assert.Nil(t, params)
+}
@@ -0,0 +1,300 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/legrch/logger"
+)
+
+// HCLogOptions configures an HCLogger
+type HCLogOptions struct {
+	// Name is the name of the logger, logged as logger.LoggerNameKey
+	Name string
+
+	// Level is the minimum level, in addition to the level of the handler.
+	// hclog.NoLevel, the default, leaves filtering to the handler.
+	Level hclog.Level
+}
+
+// HCLogger implements hclog.Logger. Names are joined with dots like hclog
+// and logged as logger.LoggerNameKey. Levels set with SetLevel are shared
+// with the loggers derived with With and Named.
+type HCLogger struct {
+	base    *slog.Logger
+	logger  *slog.Logger
+	name    string
+	implied []any
+	level   *atomic.Int32
+}
+
+var _ hclog.Logger = (*HCLogger)(nil)
+
+// NewHCLogger creates an HCLogger writing to l
+func NewHCLogger(l *slog.Logger, opts *HCLogOptions) *HCLogger {
+	if opts == nil {
+		opts = &HCLogOptions{}
+	}
+
+	level := &atomic.Int32{}
+	level.Store(int32(opts.Level))
+	return newHCLogger(l, opts.Name, nil, level)
+}
+
+// newHCLogger creates an HCLogger with the name and implied arguments
+func newHCLogger(base *slog.Logger, name string, implied []any, level *atomic.Int32) *HCLogger {
+	l := base
+	if name != "" {
+		l = l.With(logger.LoggerNameKey, name)
+	}
+	if len(implied) > 0 {
+		l = l.With(convertErrors(implied)...)
+	}
+	return &HCLogger{base: base, logger: l, name: name, implied: implied, level: level}
+}
+
+// HCLogLevel converts an hclog level to a slog level
+func HCLogLevel(level hclog.Level) slog.Level {
+	switch level {
+	case hclog.Trace:
+		return logger.LevelTrace
+	case hclog.Debug:
+		return slog.LevelDebug
+	case hclog.Warn:
+		return slog.LevelWarn
+	case hclog.Error:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Log implements hclog.Logger.
+func (h *HCLogger) Log(level hclog.Level, msg string, args ...any) {
+	h.log(level, msg, args)
+}
+
+// Trace implements hclog.Logger.
+func (h *HCLogger) Trace(msg string, args ...any) {
+	h.log(hclog.Trace, msg, args)
+}
+
+// Debug implements hclog.Logger.
+func (h *HCLogger) Debug(msg string, args ...any) {
+	h.log(hclog.Debug, msg, args)
+}
+
+// Info implements hclog.Logger.
+func (h *HCLogger) Info(msg string, args ...any) {
+	h.log(hclog.Info, msg, args)
+}
+
+// Warn implements hclog.Logger.
+func (h *HCLogger) Warn(msg string, args ...any) {
+	h.log(hclog.Warn, msg, args)
+}
+
+// Error implements hclog.Logger.
+func (h *HCLogger) Error(msg string, args ...any) {
+	h.log(hclog.Error, msg, args)
+}
+
+// IsTrace implements hclog.Logger.
+func (h *HCLogger) IsTrace() bool {
+	return h.enabled(hclog.Trace)
+}
+
+// IsDebug implements hclog.Logger.
+func (h *HCLogger) IsDebug() bool {
+	return h.enabled(hclog.Debug)
+}
+
+// IsInfo implements hclog.Logger.
+func (h *HCLogger) IsInfo() bool {
+	return h.enabled(hclog.Info)
+}
+
+// IsWarn implements hclog.Logger.
+func (h *HCLogger) IsWarn() bool {
+	return h.enabled(hclog.Warn)
+}
+
+// IsError implements hclog.Logger.
+func (h *HCLogger) IsError() bool {
+	return h.enabled(hclog.Error)
+}
+
+// ImpliedArgs implements hclog.Logger.
+func (h *HCLogger) ImpliedArgs() []any {
+	return h.implied
+}
+
+// With implements hclog.Logger.
+func (h *HCLogger) With(args ...any) hclog.Logger {
+	implied := make([]any, 0, len(h.implied)+len(args))
+	implied = append(implied, h.implied...)
+	implied = append(implied, args...)
+	return newHCLogger(h.base, h.name, implied, h.level)
+}
+
+// Name implements hclog.Logger.
+func (h *HCLogger) Name() string {
+	return h.name
+}
+
+// Named implements hclog.Logger.
+func (h *HCLogger) Named(name string) hclog.Logger {
+	if h.name != "" {
+		name = h.name + "." + name
+	}
+	return newHCLogger(h.base, name, h.implied, h.level)
+}
+
+// ResetNamed implements hclog.Logger.
+func (h *HCLogger) ResetNamed(name string) hclog.Logger {
+	return newHCLogger(h.base, name, h.implied, h.level)
+}
+
+// SetLevel implements hclog.Logger.
+func (h *HCLogger) SetLevel(level hclog.Level) {
+	h.level.Store(int32(level))
+}
+
+// GetLevel implements hclog.Logger. It returns the level set with SetLevel
+// or, if none is set, the lowest level enabled by the handler.
+func (h *HCLogger) GetLevel() hclog.Level {
+	if level := hclog.Level(h.level.Load()); level != hclog.NoLevel {
+		return level
+	}
+	for level := hclog.Trace; level <= hclog.Error; level++ {
+		if h.enabled(level) {
+			return level
+		}
+	}
+	return hclog.Off
+}
+
+// StandardLogger implements hclog.Logger.
+func (h *HCLogger) StandardLogger(opts *hclog.StandardLoggerOptions) *log.Logger {
+	return log.New(h.StandardWriter(opts), "", 0)
+}
+
+// StandardWriter implements hclog.Logger. Lines are logged at the forced
+// level, at the level inferred from a "[LEVEL]" prefix or at info.
+func (h *HCLogger) StandardWriter(opts *hclog.StandardLoggerOptions) io.Writer {
+	if opts == nil {
+		opts = &hclog.StandardLoggerOptions{}
+	}
+	return &hcLogWriter{logger: h, opts: *opts}
+}
+
+// enabled reports whether level is enabled by the set level and the handler
+func (h *HCLogger) enabled(level hclog.Level) bool {
+	if threshold := hclog.Level(h.level.Load()); threshold != hclog.NoLevel && level < threshold {
+		return false
+	}
+	return h.logger.Enabled(context.Background(), HCLogLevel(level))
+}
+
+// log writes a record attributed to the caller of the exported method.
+// It must be called directly by that method.
+func (h *HCLogger) log(level hclog.Level, msg string, args []any) {
+	if !h.enabled(level) {
+		return
+	}
+
+	// Skip runtime.Callers, log and the exported method
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+	h.write(level, msg, pcs[0], args)
+}
+
+// write handles a record with the given caller
+func (h *HCLogger) write(level hclog.Level, msg string, pc uintptr, args []any) {
+	r := slog.NewRecord(time.Now(), HCLogLevel(level), msg, pc)
+	r.Add(convertErrors(args)...)
+	_ = h.logger.Handler().Handle(context.Background(), r)
+}
+
+// convertErrors returns args with error values logged like logger.Err
+func convertErrors(args []any) []any {
+	var out []any
+	for i := 1; i < len(args); i += 2 {
+		err, ok := args[i].(error)
+		if _, isKey := args[i-1].(string); !isKey || !ok || err == nil {
+			continue
+		}
+		if out == nil {
+			out = append([]any(nil), args...)
+		}
+		out[i] = logger.ErrValue(err)
+	}
+	if out == nil {
+		return args
+	}
+	return out
+}
+
+// hcLogWriter is the io.Writer returned by HCLogger.StandardWriter
+type hcLogWriter struct {
+	logger *HCLogger
+	opts   hclog.StandardLoggerOptions
+}
+
+// hcLogPrefixes are the level prefixes inferred by hcLogWriter
+var hcLogPrefixes = []struct {
+	prefix string
+	level  hclog.Level
+}{
+	{"[TRACE]", hclog.Trace},
+	{"[DEBUG]", hclog.Debug},
+	{"[INFO]", hclog.Info},
+	{"[WARN]", hclog.Warn},
+	{"[ERROR]", hclog.Error},
+	{"[ERR]", hclog.Error},
+}
+
+// Write implements io.Writer.
+func (w *hcLogWriter) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(bytes.TrimSuffix(p, []byte{'\n'}), []byte{'\n'}) {
+		level, msg := w.parse(string(line))
+		if w.logger.enabled(level) {
+			w.logger.write(level, msg, 0, nil)
+		}
+	}
+	return len(p), nil
+}
+
+// parse returns the level and message of a line
+func (w *hcLogWriter) parse(line string) (hclog.Level, string) {
+	level := hclog.Info
+	if w.opts.ForceLevel != hclog.NoLevel {
+		level = w.opts.ForceLevel
+	} else if !w.opts.InferLevels {
+		return level, line
+	}
+
+	trimmed := line
+	if w.opts.InferLevelsWithTimestamp {
+		if i := strings.IndexByte(trimmed, '['); i >= 0 {
+			trimmed = trimmed[i:]
+		}
+	}
+	for _, p := range hcLogPrefixes {
+		if strings.HasPrefix(trimmed, p.prefix) {
+			if w.opts.ForceLevel == hclog.NoLevel {
+				level = p.level
+			}
+			return level, strings.TrimSpace(trimmed[len(p.prefix):])
+		}
+	}
+	return level, line
+}
@@ -0,0 +1,77 @@
+package adapters
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/legrch/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHCLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true}))
+
+	var hl hclog.Logger = NewHCLogger(l, &HCLogOptions{Name: "raft"})
+	hl = hl.Named("fsm").With("node", "n1")
+	hl.Warn("apply failed", "index", 7, "error", errors.New("conflict"))
+
+	assert.Equal(t, "raft.fsm", hl.Name())
+	assert.Equal(t, []any{"node", "n1"}, hl.ImpliedArgs())
+	assert.Equal(t, "other", hl.ResetNamed("other").Name())
+
+	records := decode(t, &buf)
+	require.Len(t, records, 1)
+	r := records[0]
+	assert.Equal(t, "WARN", r["level"])
+	assert.Equal(t, "apply failed", r["msg"])
+	assert.Equal(t, "raft.fsm", r[logger.LoggerNameKey])
+	assert.Equal(t, "n1", r["node"])
+	assert.InDelta(t, 7, r["index"], 0)
+	assert.Equal(t, "conflict", r["error"].(map[string]any)[logger.ErrorMessageKey])
+	assert.Equal(t, "github.com/legrch/logger/adapters.TestHCLogger", r["source"].(map[string]any)["function"])
+}
+
+func TestHCLoggerLevels(t *testing.T) {
+	mock := &logger.MockLogger{}
+	hl := NewHCLogger(slog.New(mock), nil)
+
+	assert.True(t, hl.IsTrace())
+	assert.Equal(t, hclog.Trace, hl.GetLevel())
+
+	hl.SetLevel(hclog.Warn)
+	assert.False(t, hl.IsInfo())
+	assert.True(t, hl.IsWarn())
+	assert.Equal(t, hclog.Warn, hl.GetLevel())
+
+	hl.Info("hidden")
+	hl.Log(hclog.Error, "shown")
+	hl.Trace("hidden")
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 1)
+	assert.Equal(t, slog.LevelError, logs[0].Level)
+	assert.Equal(t, logger.LevelTrace, HCLogLevel(hclog.Trace))
+}
+
+func TestHCLoggerStandardLogger(t *testing.T) {
+	mock := &logger.MockLogger{}
+	hl := NewHCLogger(slog.New(mock), nil)
+
+	hl.StandardLogger(&hclog.StandardLoggerOptions{InferLevels: true}).Print("[ERR] disk full")
+	hl.StandardLogger(nil).Print("[WARN] kept as is")
+	hl.StandardLogger(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}).Print("[ERROR] forced")
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 3)
+	assert.Equal(t, slog.LevelError, logs[0].Level)
+	assert.Equal(t, "disk full", logs[0].Message)
+	assert.Equal(t, slog.LevelInfo, logs[1].Level)
+	assert.Equal(t, "[WARN] kept as is", logs[1].Message)
+	assert.Equal(t, slog.LevelDebug, logs[2].Level)
+	assert.Equal(t, "forced", logs[2].Message)
+}
@@ -0,0 +1,81 @@
+package adapters
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"time"
+
+	"github.com/legrch/logger"
+)
+
+// kgoPrefix is the prefix of the functions of franz-go
+const kgoPrefix = "github.com/twmb/franz-go"
+
+// franz-go log levels, mirroring the kgo.LogLevel constants
+const (
+	kgoLevelNone = iota
+	kgoLevelError
+	kgoLevelWarn
+	kgoLevelInfo
+	kgoLevelDebug
+)
+
+// NewSaramaLogger returns a logger implementing sarama.StdLogger, to assign
+// to sarama.Logger or sarama.DebugLogger, that logs each line to l at level
+func NewSaramaLogger(l *slog.Logger, level slog.Level) *log.Logger {
+	return logger.NewStdLogger(l, level)
+}
+
+// KgoLogger implements the Logger interface of the franz-go kgo package
+// when instantiated with kgo.LogLevel:
+//
+//	kgo.WithLogger(adapters.NewKgoLogger[kgo.LogLevel](l))
+type KgoLogger[L ~int8] struct {
+	logger *slog.Logger
+}
+
+// NewKgoLogger creates a KgoLogger writing to l
+func NewKgoLogger[L ~int8](l *slog.Logger) *KgoLogger[L] {
+	return &KgoLogger[L]{logger: l}
+}
+
+// KgoLevel converts a franz-go log level to a slog level
+func KgoLevel[L ~int8](level L) slog.Level {
+	switch level {
+	case kgoLevelError:
+		return slog.LevelError
+	case kgoLevelWarn:
+		return slog.LevelWarn
+	case kgoLevelInfo:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
+
+// Level implements kgo.Logger, returning the most verbose level enabled by the handler.
+func (k *KgoLogger[L]) Level() L {
+	ctx := context.Background()
+	for _, level := range []L{kgoLevelDebug, kgoLevelInfo, kgoLevelWarn, kgoLevelError} {
+		if k.logger.Enabled(ctx, KgoLevel(level)) {
+			return level
+		}
+	}
+	return kgoLevelNone
+}
+
+// Log implements kgo.Logger. Error values are logged like logger.Err.
+func (k *KgoLogger[L]) Log(level L, msg string, keyvals ...any) {
+	if level == kgoLevelNone {
+		return
+	}
+	ctx := context.Background()
+	if !k.logger.Enabled(ctx, KgoLevel(level)) {
+		return
+	}
+
+	r := slog.NewRecord(time.Now(), KgoLevel(level), msg, libraryCaller(kgoPrefix))
+	r.Add(convertErrors(keyvals)...)
+	_ = k.logger.Handler().Handle(ctx, r)
+}
@@ -0,0 +1,59 @@
+package adapters
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/legrch/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// saramaStdLogger is the StdLogger interface of sarama
+type saramaStdLogger interface {
+	Print(v ...any)
+	Printf(format string, v ...any)
+	Println(v ...any)
+}
+
+// fakeLogLevel and fakeKgoLogger mirror kgo.LogLevel and kgo.Logger
+type (
+	fakeLogLevel  int8
+	fakeKgoLogger interface {
+		Level() fakeLogLevel
+		Log(level fakeLogLevel, msg string, keyvals ...any)
+	}
+)
+
+func TestSaramaLogger(t *testing.T) {
+	mock := &logger.MockLogger{}
+	var sl saramaStdLogger = NewSaramaLogger(slog.New(mock), slog.LevelInfo)
+
+	sl.Printf("client/metadata fetching metadata for topic %s\n", "orders")
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 1)
+	assert.Equal(t, "client/metadata fetching metadata for topic orders", logs[0].Message)
+}
+
+func TestKgoLogger(t *testing.T) {
+	mock := &logger.MockLogger{}
+	var kl fakeKgoLogger = NewKgoLogger[fakeLogLevel](slog.New(mock))
+
+	assert.Equal(t, fakeLogLevel(kgoLevelDebug), kl.Level())
+
+	kl.Log(kgoLevelWarn, "unable to commit", "group", "g1", "err", errors.New("rebalance"))
+	kl.Log(kgoLevelNone, "ignored")
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 1)
+	assert.Equal(t, slog.LevelWarn, logs[0].Level)
+	require.Len(t, logs[0].Attrs, 2)
+	assert.Equal(t, "g1", logs[0].Attrs[0].Value.String())
+	assert.Equal(t, slog.KindLogValuer, logs[0].Attrs[1].Value.Kind())
+
+	warn := slog.New(slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	assert.Equal(t, fakeLogLevel(kgoLevelWarn), NewKgoLogger[fakeLogLevel](warn).Level())
+}
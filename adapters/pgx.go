@@ -0,0 +1,62 @@
+package adapters
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"github.com/jackc/pgx/v5/tracelog"
+	"github.com/legrch/logger"
+)
+
+// pgxPrefix is the prefix of the functions of pgx
+const pgxPrefix = "github.com/jackc/pgx/v5"
+
+// PgxLogger implements tracelog.Logger for a pgx tracelog.TraceLog
+type PgxLogger struct {
+	logger *slog.Logger
+}
+
+var _ tracelog.Logger = (*PgxLogger)(nil)
+
+// NewPgxLogger creates a PgxLogger writing to l
+func NewPgxLogger(l *slog.Logger) *PgxLogger {
+	return &PgxLogger{logger: l}
+}
+
+// PgxLevel converts a pgx tracelog level to a slog level
+func PgxLevel(level tracelog.LogLevel) slog.Level {
+	switch level {
+	case tracelog.LogLevelTrace:
+		return logger.LevelTrace
+	case tracelog.LogLevelDebug:
+		return slog.LevelDebug
+	case tracelog.LogLevelInfo:
+		return slog.LevelInfo
+	case tracelog.LogLevelWarn:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// Log implements tracelog.Logger. The data is logged in key order with
+// error values logged like logger.Err.
+func (p *PgxLogger) Log(ctx context.Context, level tracelog.LogLevel, msg string, data map[string]any) {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		if err, ok := data[k].(error); ok && err != nil {
+			attrs = append(attrs, slog.Attr{Key: k, Value: logger.ErrValue(err)})
+			continue
+		}
+		attrs = append(attrs, slog.Any(k, data[k]))
+	}
+
+	write(ctx, p.logger, PgxLevel(level), msg, libraryCaller(pgxPrefix), attrs...)
+}
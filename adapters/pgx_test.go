@@ -0,0 +1,41 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/tracelog"
+	"github.com/legrch/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPgxLogger(t *testing.T) {
+	mock := &logger.MockLogger{}
+	var pl tracelog.Logger = NewPgxLogger(slog.New(mock))
+
+	pl.Log(context.Background(), tracelog.LogLevelError, "Query", map[string]any{
+		"sql":  "select 1",
+		"time": time.Millisecond,
+		"err":  errors.New("timeout"),
+	})
+	pl.Log(context.Background(), tracelog.LogLevelTrace, "Prepare", nil)
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 2)
+	assert.Equal(t, slog.LevelError, logs[0].Level)
+	assert.Equal(t, "Query", logs[0].Message)
+
+	keys := make([]string, 0, len(logs[0].Attrs))
+	for _, attr := range logs[0].Attrs {
+		keys = append(keys, attr.Key)
+	}
+	assert.Equal(t, []string{"err", "sql", "time"}, keys)
+	assert.Equal(t, slog.KindLogValuer, logs[0].Attrs[0].Value.Kind())
+
+	assert.Equal(t, logger.LevelTrace, logs[1].Level)
+	assert.Equal(t, slog.LevelWarn, PgxLevel(tracelog.LogLevelWarn))
+}
@@ -0,0 +1,36 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// redisPrefix is the prefix of the functions of go-redis
+const redisPrefix = "github.com/redis/go-redis/v9"
+
+// RedisLogger implements the logging interface of go-redis, set with redis.SetLogger
+type RedisLogger struct {
+	logger *slog.Logger
+	level  slog.Level
+}
+
+// NewRedisLogger creates a RedisLogger writing to l at level. go-redis only
+// logs failures such as dropped connections, so slog.LevelWarn is typical.
+func NewRedisLogger(l *slog.Logger, level slog.Level) *RedisLogger {
+	return &RedisLogger{logger: l, level: level}
+}
+
+// Printf implements the go-redis logging interface.
+func (r *RedisLogger) Printf(ctx context.Context, format string, v ...any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !r.logger.Enabled(ctx, r.level) {
+		return
+	}
+
+	msg := strings.TrimSuffix(fmt.Sprintf(format, v...), "\n")
+	write(ctx, r.logger, r.level, msg, libraryCaller(redisPrefix))
+}
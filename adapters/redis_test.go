@@ -0,0 +1,27 @@
+package adapters
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/legrch/logger"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RedisLogger must be accepted by redis.SetLogger
+var _ = func() { redis.SetLogger(&RedisLogger{}) }
+
+func TestRedisLogger(t *testing.T) {
+	mock := &logger.MockLogger{}
+	rl := NewRedisLogger(slog.New(mock), slog.LevelWarn)
+
+	rl.Printf(context.Background(), "redis: discarding bad PubSub connection: %s\n", "EOF")
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 1)
+	assert.Equal(t, slog.LevelWarn, logs[0].Level)
+	assert.Equal(t, "redis: discarding bad PubSub connection: EOF", logs[0].Message)
+}
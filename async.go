@@ -0,0 +1,349 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// OverflowPolicy determines what an AsyncHandler does when its queue is full
+type OverflowPolicy string
+
+// Overflow policies
+const (
+	// OverflowBlock waits for space in the queue
+	OverflowBlock OverflowPolicy = "block"
+
+	// OverflowDropNewest drops the record being logged
+	OverflowDropNewest OverflowPolicy = "drop_newest"
+
+	// OverflowDropOldest drops the oldest queued record to make space
+	OverflowDropOldest OverflowPolicy = "drop_oldest"
+
+	// OverflowDropBelowLevel drops records below AsyncOptions.DropBelow and
+	// waits for space for the others
+	OverflowDropBelowLevel OverflowPolicy = "drop_below_level"
+)
+
+// Async defaults
+const (
+	DefaultAsyncQueueSize = 1024
+	DefaultAsyncBatchSize = 64
+)
+
+// ErrHandlerClosed is returned when logging to a closed handler
+var ErrHandlerClosed = errors.New("logger: handler closed")
+
+// AsyncOptions configures an AsyncHandler
+type AsyncOptions struct {
+	// QueueSize is the capacity of the ring buffer, defaults to DefaultAsyncQueueSize
+	QueueSize int
+
+	// BatchSize is the maximum number of records written per batch, defaults to DefaultAsyncBatchSize
+	BatchSize int
+
+	// Overflow is the policy applied when the queue is full, defaults to OverflowBlock
+	Overflow OverflowPolicy
+
+	// DropBelow is the level below which records are dropped by OverflowDropBelowLevel
+	DropBelow slog.Level
+
+	// Flusher is flushed after each batch, defaults to the next handler if it
+	// implements Flusher
+	Flusher Flusher
+
+	// OnError is called with errors returned by the next handler and Flusher,
+	// and with the panics of the next handler
+	OnError func(err error)
+}
+
+// AsyncStats holds the metrics of an AsyncHandler
+type AsyncStats struct {
+	// QueueDepth is the number of queued records
+	QueueDepth int
+
+	// QueueCapacity is the size of the queue
+	QueueCapacity int
+
+	// Handled is the number of records passed to the next handler
+	Handled uint64
+
+	// Dropped is the number of records dropped on overflow
+	Dropped uint64
+
+	// Errors is the number of errors and panics of the next handler
+	Errors uint64
+}
+
+// asyncEntry is a queued record with the handler it is written to
+type asyncEntry struct {
+	ctx     context.Context
+	handler slog.Handler
+	record  slog.Record
+}
+
+// asyncQueue is the ring buffer and writer state shared by derived handlers
+type asyncQueue struct {
+	opts AsyncOptions
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	buf      []asyncEntry
+	head     int
+	size     int
+	closed   bool
+	enqueued uint64
+	done     uint64
+	handled  uint64
+	dropped  uint64
+	errors   uint64
+	exited   chan struct{}
+}
+
+// AsyncHandler is a slog.Handler that queues records in a bounded ring buffer
+// and writes them to the next handler from a background goroutine.
+type AsyncHandler struct {
+	next  slog.Handler
+	queue *asyncQueue
+}
+
+// NewAsyncHandler creates a new AsyncHandler wrapping next and starts its
+// writer goroutine. Call Close to drain the queue and stop it.
+func NewAsyncHandler(next slog.Handler, opts *AsyncOptions) *AsyncHandler {
+	if opts == nil {
+		opts = &AsyncOptions{}
+	}
+
+	q := &asyncQueue{opts: *opts, exited: make(chan struct{})}
+	if q.opts.QueueSize <= 0 {
+		q.opts.QueueSize = DefaultAsyncQueueSize
+	}
+	if q.opts.BatchSize <= 0 {
+		q.opts.BatchSize = DefaultAsyncBatchSize
+	}
+	if q.opts.Overflow == "" {
+		q.opts.Overflow = OverflowBlock
+	}
+	if q.opts.Flusher == nil {
+		q.opts.Flusher, _ = next.(Flusher)
+	}
+	q.buf = make([]asyncEntry, q.opts.QueueSize)
+	q.cond = sync.NewCond(&q.mu)
+
+	go q.run()
+
+	return &AsyncHandler{next: next, queue: q}
+}
+
+// ParseOverflowPolicy parses an overflow policy name
+func ParseOverflowPolicy(s string) (OverflowPolicy, error) {
+	switch policy := OverflowPolicy(s); policy {
+	case OverflowBlock, OverflowDropNewest, OverflowDropOldest, OverflowDropBelowLevel:
+		return policy, nil
+	case "":
+		return OverflowBlock, nil
+	default:
+		return "", fmt.Errorf("unknown overflow policy: %s", s)
+	}
+}
+
+// Enabled implements slog.Handler.
+func (h *AsyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+//
+//nolint:gocritic // Cannot change signature due to interface contract
+func (h *AsyncHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return h.queue.push(asyncEntry{
+		// The record outlives the call, so it must not be canceled with it
+		ctx:     context.WithoutCancel(ctx),
+		handler: h.next,
+		record:  r.Clone(),
+	})
+}
+
+// WithAttrs implements slog.Handler.
+func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AsyncHandler{next: h.next.WithAttrs(attrs), queue: h.queue}
+}
+
+// WithGroup implements slog.Handler.
+func (h *AsyncHandler) WithGroup(name string) slog.Handler {
+	return &AsyncHandler{next: h.next.WithGroup(name), queue: h.queue}
+}
+
+// Flush waits until every record queued before the call has been written
+func (h *AsyncHandler) Flush(ctx context.Context) error {
+	q := h.queue
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stop := context.AfterFunc(ctx, q.broadcast)
+	defer stop()
+
+	target := q.enqueued
+	for q.done < target {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("flush: %w", err)
+		}
+		q.cond.Wait()
+	}
+
+	return nil
+}
+
+// Close stops accepting records, drains the queue and stops the writer goroutine.
+// Records logged after Close return ErrHandlerClosed.
+func (h *AsyncHandler) Close(ctx context.Context) error {
+	q := h.queue
+
+	q.mu.Lock()
+	q.closed = true
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	select {
+	case <-q.exited:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("close: %w", ctx.Err())
+	}
+}
+
+// Stats returns the queue metrics, shared by all handlers derived from this one
+func (h *AsyncHandler) Stats() AsyncStats {
+	q := h.queue
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return AsyncStats{
+		QueueDepth:    q.size,
+		QueueCapacity: len(q.buf),
+		Handled:       q.handled,
+		Dropped:       q.dropped,
+		Errors:        q.errors,
+	}
+}
+
+// broadcast wakes up all waiters
+func (q *asyncQueue) broadcast() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.cond.Broadcast()
+}
+
+// push adds an entry to the queue, applying the overflow policy when it is full
+func (q *asyncQueue) push(e asyncEntry) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for !q.closed && q.size == len(q.buf) {
+		switch q.opts.Overflow {
+		case OverflowDropNewest:
+			q.dropped++
+			return nil
+		case OverflowDropOldest:
+			q.buf[q.head] = asyncEntry{}
+			q.head = (q.head + 1) % len(q.buf)
+			q.size--
+			q.dropped++
+			q.done++
+		case OverflowDropBelowLevel:
+			if e.record.Level < q.opts.DropBelow {
+				q.dropped++
+				return nil
+			}
+			q.cond.Wait()
+		default:
+			q.cond.Wait()
+		}
+	}
+
+	if q.closed {
+		return ErrHandlerClosed
+	}
+
+	q.buf[(q.head+q.size)%len(q.buf)] = e
+	q.size++
+	q.enqueued++
+	q.cond.Broadcast()
+
+	return nil
+}
+
+// run writes queued records in batches until the queue is closed and drained
+func (q *asyncQueue) run() {
+	defer close(q.exited)
+
+	batch := make([]asyncEntry, 0, q.opts.BatchSize)
+	for {
+		q.mu.Lock()
+		for q.size == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		if q.size == 0 {
+			q.mu.Unlock()
+			return
+		}
+
+		batch = batch[:0]
+		for q.size > 0 && len(batch) < q.opts.BatchSize {
+			batch = append(batch, q.buf[q.head])
+			q.buf[q.head] = asyncEntry{}
+			q.head = (q.head + 1) % len(q.buf)
+			q.size--
+		}
+		q.cond.Broadcast()
+		q.mu.Unlock()
+
+		var errs uint64
+		for i := range batch {
+			if err := batch[i].handle(); err != nil {
+				errs++
+				q.onError(err)
+			}
+		}
+		if q.opts.Flusher != nil {
+			if err := q.opts.Flusher.Flush(context.Background()); err != nil {
+				errs++
+				q.onError(fmt.Errorf("flush: %w", err))
+			}
+		}
+
+		q.mu.Lock()
+		q.done += uint64(len(batch))
+		q.handled += uint64(len(batch))
+		q.errors += errs
+		q.cond.Broadcast()
+		q.mu.Unlock()
+	}
+}
+
+// onError passes err to AsyncOptions.OnError
+func (q *asyncQueue) onError(err error) {
+	if q.opts.OnError != nil {
+		q.opts.OnError(err)
+	}
+}
+
+// handle writes the entry to its handler. A panic is returned as an error so
+// that it does not stop the writer goroutine.
+func (e *asyncEntry) handle() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panic: %v", r)
+		}
+	}()
+
+	return e.handler.Handle(e.ctx, e.record)
+}
@@ -0,0 +1,217 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gateHandler blocks Handle until the gate is opened
+type gateHandler struct {
+	*MockLogger
+	gate chan struct{}
+}
+
+func (h *gateHandler) Handle(ctx context.Context, r slog.Record) error {
+	<-h.gate
+	return h.MockLogger.Handle(ctx, r)
+}
+
+func TestAsyncHandler(t *testing.T) {
+	mock := &MockLogger{}
+	handler := NewAsyncHandler(mock, nil)
+	log := slog.New(handler)
+
+	for i := range 100 {
+		log.Info("message", "i", i)
+	}
+
+	require.NoError(t, handler.Flush(context.Background()))
+	logs := mock.GetLogs()
+	require.Len(t, logs, 100)
+	assert.Equal(t, int64(99), logs[99].Attrs[0].Value.Int64())
+
+	require.NoError(t, handler.Close(context.Background()))
+	require.ErrorIs(t, handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "late", 0)), ErrHandlerClosed)
+
+	stats := handler.Stats()
+	assert.Equal(t, uint64(100), stats.Handled)
+	assert.Equal(t, 0, stats.QueueDepth)
+	assert.Equal(t, DefaultAsyncQueueSize, stats.QueueCapacity)
+}
+
+func TestAsyncHandlerOverflow(t *testing.T) {
+	tests := []struct {
+		policy   OverflowPolicy
+		wantMsgs []string
+	}{
+		{OverflowDropNewest, []string{"first", "second", "third"}},
+		{OverflowDropOldest, []string{"first", "third", "fourth"}},
+		{OverflowDropBelowLevel, []string{"first", "second", "third"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			h := &gateHandler{MockLogger: &MockLogger{}, gate: make(chan struct{})}
+			handler := NewAsyncHandler(h, &AsyncOptions{QueueSize: 2, BatchSize: 1, Overflow: tt.policy, DropBelow: slog.LevelWarn})
+			log := slog.New(handler)
+
+			// The writer takes "first" and blocks, leaving room for two queued records
+			log.Info("first")
+			require.Eventually(t, func() bool { return handler.Stats().QueueDepth == 0 }, time.Second, time.Millisecond)
+			log.Info("second")
+			log.Info("third")
+			log.Info("fourth")
+
+			close(h.gate)
+			require.NoError(t, handler.Close(context.Background()))
+
+			var msgs []string
+			for _, entry := range h.GetLogs() {
+				msgs = append(msgs, entry.Message)
+			}
+			assert.Equal(t, tt.wantMsgs, msgs)
+			assert.Equal(t, uint64(1), handler.Stats().Dropped)
+		})
+	}
+}
+
+func TestAsyncHandlerBlock(t *testing.T) {
+	h := &gateHandler{MockLogger: &MockLogger{}, gate: make(chan struct{})}
+	handler := NewAsyncHandler(h, &AsyncOptions{QueueSize: 1, BatchSize: 1})
+	log := slog.New(handler)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range 5 {
+			log.Info("message")
+		}
+	}()
+
+	require.Eventually(t, func() bool { return handler.Stats().QueueDepth == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, handler.Flush(ctx), context.DeadlineExceeded)
+
+	close(h.gate)
+	wg.Wait()
+	require.NoError(t, handler.Close(context.Background()))
+	assert.Len(t, h.GetLogs(), 5)
+	assert.Zero(t, handler.Stats().Dropped)
+}
+
+func TestAsyncHandlerErrors(t *testing.T) {
+	var mu sync.Mutex
+	var got []error
+	handler := NewAsyncHandler(&errorHandler{Handler: &MockLogger{}}, &AsyncOptions{OnError: func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, err)
+	}})
+
+	slog.New(handler).Info("fails")
+	require.NoError(t, handler.Close(context.Background()))
+
+	assert.Equal(t, uint64(1), handler.Stats().Errors)
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, got, 1)
+	assert.EqualError(t, got[0], "write failed")
+}
+
+// errorHandler fails every Handle call
+type errorHandler struct {
+	slog.Handler
+}
+
+func (*errorHandler) Handle(context.Context, slog.Record) error {
+	return errors.New("write failed")
+}
+
+// panicHandler panics on records with the message "panic"
+type panicHandler struct {
+	*MockLogger
+}
+
+func (h *panicHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Message == "panic" {
+		panic("boom")
+	}
+	return h.MockLogger.Handle(ctx, r)
+}
+
+func TestAsyncHandlerPanic(t *testing.T) {
+	var mu sync.Mutex
+	var got []error
+	h := &panicHandler{MockLogger: &MockLogger{}}
+	handler := NewAsyncHandler(h, &AsyncOptions{OnError: func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, err)
+	}})
+
+	log := slog.New(handler)
+	log.Info("before")
+	log.Info("panic")
+	log.Info("after")
+	require.NoError(t, handler.Close(context.Background()))
+
+	logs := h.GetLogs()
+	require.Len(t, logs, 2)
+	assert.Equal(t, "after", logs[1].Message)
+	assert.Equal(t, uint64(1), handler.Stats().Errors)
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, got, 1)
+	assert.EqualError(t, got[0], "handler panic: boom")
+}
+
+// flushCounter counts the Flush calls
+type flushCounter struct {
+	*MockLogger
+	mu      sync.Mutex
+	flushes int
+}
+
+func (h *flushCounter) Flush(context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.flushes++
+	return nil
+}
+
+func TestAsyncHandlerFlushesBatches(t *testing.T) {
+	h := &flushCounter{MockLogger: &MockLogger{}}
+	handler := NewAsyncHandler(h, nil)
+
+	slog.New(handler).Info("message")
+	require.NoError(t, handler.Close(context.Background()))
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	assert.Equal(t, 1, h.flushes)
+}
+
+func TestParseOverflowPolicy(t *testing.T) {
+	policy, err := ParseOverflowPolicy("")
+	require.NoError(t, err)
+	assert.Equal(t, OverflowBlock, policy)
+
+	_, err = ParseOverflowPolicy("explode")
+	require.Error(t, err)
+
+	_, err = Open(&Config{Level: "info", EnableAsync: true, AsyncOverflow: "explode"})
+	require.Error(t, err)
+
+	_, err = Open(&Config{Level: "info", EnableAsync: true, AsyncDropBelow: "loud"})
+	require.Error(t, err)
+}
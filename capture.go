@@ -0,0 +1,299 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Capture attribute keys
+const (
+	StreamKey    = "stream"
+	PIDKey       = "pid"
+	CommandKey   = "cmd"
+	ArgsKey      = "args"
+	PartialKey   = "partial"
+	TruncatedKey = "truncated"
+)
+
+// Stream names
+const (
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+)
+
+// DefaultMaxLineLength is the default maximum length of a captured line
+const DefaultMaxLineLength = 16 * 1024
+
+var (
+	// capturedStderr is the original stderr while CaptureStderr redirects it
+	capturedStderr io.Writer
+
+	// capturedStderrMu protects capturedStderr
+	capturedStderrMu sync.RWMutex
+)
+
+// errorOutput returns where the package reports its own errors: stderr, or
+// the original stderr while it is captured, so that the reports are not
+// logged through the logger that failed
+func errorOutput() io.Writer {
+	capturedStderrMu.RLock()
+	defer capturedStderrMu.RUnlock()
+	if capturedStderr != nil {
+		return capturedStderr
+	}
+	return os.Stderr
+}
+
+// setCapturedStderr sets the original stderr, or clears it if w is nil
+func setCapturedStderr(w io.Writer) {
+	capturedStderrMu.Lock()
+	defer capturedStderrMu.Unlock()
+	capturedStderr = w
+}
+
+// CaptureOptions configures how captured output is logged
+type CaptureOptions struct {
+	// Logger receives the records, defaults to Default()
+	Logger *slog.Logger
+
+	// StdoutLevel is the level of stdout lines
+	StdoutLevel slog.Level
+
+	// StderrLevel is the level of stderr lines
+	StderrLevel slog.Level
+
+	// MaxLineLength caps the length of a line; the rest of a longer line is
+	// dropped and the record is marked truncated. Defaults to DefaultMaxLineLength.
+	MaxLineLength int
+
+	// LogArgs adds the command arguments, which may contain secrets
+	LogArgs bool
+
+	// Attrs are added to every record
+	Attrs []slog.Attr
+}
+
+// logger returns the configured logger
+func (o *CaptureOptions) logger() *slog.Logger {
+	if o.Logger != nil {
+		return o.Logger
+	}
+	return Default()
+}
+
+// maxLine returns the configured line cap
+func (o *CaptureOptions) maxLine() int {
+	if o.MaxLineLength > 0 {
+		return o.MaxLineLength
+	}
+	return DefaultMaxLineLength
+}
+
+// LineWriter is an io.WriteCloser that logs each line written to it as a
+// record. Close logs an unterminated last line marked as partial.
+type LineWriter struct {
+	logger *slog.Logger
+	level  slog.Level
+	max    int
+	attrs  func() []slog.Attr
+
+	mu       sync.Mutex
+	buf      []byte
+	skipping bool
+}
+
+// NewLineWriter creates a LineWriter logging lines of at most maxLine bytes
+// to l at level with the given attributes
+func NewLineWriter(l *slog.Logger, level slog.Level, maxLine int, attrs ...slog.Attr) *LineWriter {
+	if maxLine <= 0 {
+		maxLine = DefaultMaxLineLength
+	}
+	return &LineWriter{
+		logger: l,
+		level:  level,
+		max:    maxLine,
+		attrs:  func() []slog.Attr { return attrs },
+	}
+}
+
+// Write implements io.Writer.
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			w.appendLine(p)
+			break
+		}
+
+		w.appendLine(p[:i])
+		if !w.skipping {
+			w.emit(w.buf, false, false)
+		}
+		w.buf = w.buf[:0]
+		w.skipping = false
+		p = p[i+1:]
+	}
+
+	return n, nil
+}
+
+// appendLine buffers part of a line, logging it as truncated once it exceeds the cap.
+// It must be called with the lock held.
+func (w *LineWriter) appendLine(p []byte) {
+	if w.skipping {
+		return
+	}
+
+	w.buf = append(w.buf, p...)
+	if len(w.buf) > w.max {
+		// Cut at a rune boundary so the record stays valid UTF-8
+		cut := w.max
+		for cut > 0 && !utf8.RuneStart(w.buf[cut]) {
+			cut--
+		}
+		if cut == 0 {
+			cut = w.max
+		}
+		w.emit(w.buf[:cut], false, true)
+		w.buf = w.buf[:0]
+		w.skipping = true
+	}
+}
+
+// Close logs the buffered partial line, if any
+func (w *LineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 && !w.skipping {
+		w.emit(w.buf, true, false)
+	}
+	w.buf = w.buf[:0]
+	w.skipping = false
+
+	return nil
+}
+
+// emit logs a line. It must be called with the lock held.
+func (w *LineWriter) emit(line []byte, partial, truncated bool) {
+	ctx := context.Background()
+	if !w.logger.Enabled(ctx, w.level) {
+		return
+	}
+
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	r := slog.NewRecord(time.Now(), w.level, string(line), 0)
+	r.AddAttrs(w.attrs()...)
+	if partial {
+		r.AddAttrs(slog.Bool(PartialKey, true))
+	}
+	if truncated {
+		r.AddAttrs(slog.Bool(TruncatedKey, true))
+	}
+	_ = w.logger.Handler().Handle(ctx, r)
+}
+
+// CmdCapture logs the output of a command started with CaptureCmd
+type CmdCapture struct {
+	stdout *LineWriter
+	stderr *LineWriter
+}
+
+// CaptureCmd sets the stdout and stderr of cmd to writers logging each line
+// with the stream, pid and cmd attributes. Call Close after cmd.Wait to log
+// unterminated last lines.
+func CaptureCmd(cmd *exec.Cmd, opts *CaptureOptions) *CmdCapture {
+	if opts == nil {
+		opts = &CaptureOptions{}
+	}
+
+	attrs := func(stream string) func() []slog.Attr {
+		return func() []slog.Attr {
+			attrs := make([]slog.Attr, 0, len(opts.Attrs)+4)
+			attrs = append(attrs, slog.String(StreamKey, stream))
+			if cmd.Process != nil {
+				attrs = append(attrs, slog.Int(PIDKey, cmd.Process.Pid))
+			}
+			attrs = append(attrs, slog.String(CommandKey, filepath.Base(cmd.Path)))
+			if opts.LogArgs && len(cmd.Args) > 1 {
+				attrs = append(attrs, slog.Any(ArgsKey, cmd.Args[1:]))
+			}
+			return append(attrs, opts.Attrs...)
+		}
+	}
+
+	c := &CmdCapture{
+		stdout: NewLineWriter(opts.logger(), opts.StdoutLevel, opts.maxLine()),
+		stderr: NewLineWriter(opts.logger(), opts.StderrLevel, opts.maxLine()),
+	}
+	c.stdout.attrs = attrs(StreamStdout)
+	c.stderr.attrs = attrs(StreamStderr)
+
+	cmd.Stdout = c.stdout
+	cmd.Stderr = c.stderr
+
+	return c
+}
+
+// Close logs the unterminated last lines of both streams
+func (c *CmdCapture) Close() error {
+	return errors.Join(c.stdout.Close(), c.stderr.Close())
+}
+
+// RunCmd runs cmd with its output captured by CaptureCmd
+func RunCmd(cmd *exec.Cmd, opts *CaptureOptions) error {
+	c := CaptureCmd(cmd, opts)
+	err := cmd.Run()
+	return errors.Join(err, c.Close())
+}
+
+// FDCapture logs the lines written to a redirected file descriptor
+type FDCapture struct {
+	writer  *LineWriter
+	reader  io.Closer
+	restore func() error
+	done    chan struct{}
+	once    sync.Once
+	err     error
+}
+
+// copyLines copies r to the writer until EOF and marks the capture done
+func (c *FDCapture) copyLines(r io.ReadCloser) {
+	defer close(c.done)
+	_, _ = io.Copy(c.writer, r)
+	_ = r.Close()
+}
+
+// Close restores the file descriptor, waits for the captured output to be
+// logged and logs an unterminated last line. Child processes that inherited
+// the file descriptor keep the capture open until they exit; if ctx is done
+// first, the output they write afterwards is discarded and ctx.Err is returned.
+func (c *FDCapture) Close(ctx context.Context) error {
+	c.once.Do(func() {
+		err := c.restore()
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			// Closing the read end ends the copy without waiting for EOF
+			_ = c.reader.Close()
+			<-c.done
+			err = errors.Join(err, fmt.Errorf("close capture: %w", ctx.Err()))
+		}
+		c.err = errors.Join(err, c.writer.Close())
+	})
+	return c.err
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package logger
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CaptureStderr redirects file descriptor 2 of the process into records.
+// It is only supported on Unix systems.
+func CaptureStderr(*CaptureOptions) (*FDCapture, error) {
+	return nil, fmt.Errorf("capture stderr: %w", errors.ErrUnsupported)
+}
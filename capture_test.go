@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func attrsOf(entry LogEntry) map[string]slog.Value {
+	attrs := make(map[string]slog.Value, len(entry.Attrs))
+	for _, attr := range entry.Attrs {
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs
+}
+
+func TestLineWriter(t *testing.T) {
+	mock := &MockLogger{}
+	w := NewLineWriter(slog.New(mock), slog.LevelWarn, 8, slog.String("tool", "x"))
+
+	_, _ = w.Write([]byte("first\r\nsec"))
+	_, _ = w.Write([]byte("ond\n"))
+	_, _ = w.Write([]byte("a very long line\nshort\nrest"))
+	require.NoError(t, w.Close())
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 5)
+
+	assert.Equal(t, "first", logs[0].Message)
+	assert.Equal(t, slog.LevelWarn, logs[0].Level)
+	assert.Equal(t, []slog.Attr{slog.String("tool", "x")}, logs[0].Attrs)
+	assert.Equal(t, "second", logs[1].Message)
+
+	assert.Equal(t, "a very l", logs[2].Message)
+	assert.True(t, attrsOf(logs[2])[TruncatedKey].Bool())
+	assert.Equal(t, "short", logs[3].Message)
+
+	assert.Equal(t, "rest", logs[4].Message)
+	assert.True(t, attrsOf(logs[4])[PartialKey].Bool())
+
+	// Truncation does not split multi-byte runes
+	mock = &MockLogger{}
+	w = NewLineWriter(slog.New(mock), slog.LevelWarn, 8)
+	_, _ = w.Write([]byte("hello wörld\n"))
+	logs = mock.GetLogs()
+	require.Len(t, logs, 1)
+	assert.Equal(t, "hello w", logs[0].Message)
+	assert.True(t, utf8.ValidString(logs[0].Message))
+}
+
+func TestRunCmd(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	mock := &MockLogger{}
+	cmd := exec.Command("sh", "-c", "echo out; echo err >&2; printf tail")
+	require.NoError(t, RunCmd(cmd, &CaptureOptions{
+		Logger:      slog.New(mock),
+		StderrLevel: slog.LevelWarn,
+		LogArgs:     true,
+	}))
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 3)
+
+	byMsg := make(map[string]LogEntry)
+	for _, entry := range logs {
+		byMsg[entry.Message] = entry
+	}
+
+	out := attrsOf(byMsg["out"])
+	assert.Equal(t, StreamStdout, out[StreamKey].String())
+	assert.Equal(t, int64(cmd.Process.Pid), out[PIDKey].Int64())
+	assert.Equal(t, "sh", out[CommandKey].String())
+	assert.Equal(t, []string{"-c", "echo out; echo err >&2; printf tail"}, out[ArgsKey].Any())
+
+	assert.Equal(t, slog.LevelWarn, byMsg["err"].Level)
+	assert.Equal(t, StreamStderr, attrsOf(byMsg["err"])[StreamKey].String())
+
+	assert.True(t, attrsOf(byMsg["tail"])[PartialKey].Bool())
+}
+
+func TestCaptureStderr(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires unix")
+	}
+
+	mock := &MockLogger{}
+	c, err := CaptureStderr(&CaptureOptions{Logger: slog.New(mock), StderrLevel: slog.LevelError})
+	require.NoError(t, err)
+
+	fmt.Fprintln(os.Stderr, "from fd 2")
+	fmt.Fprint(os.Stderr, "unterminated")
+
+	// The package reports its own errors to the original stderr meanwhile
+	assert.NotSame(t, os.Stderr, errorOutput())
+	require.NoError(t, c.Close(context.Background()))
+	require.NoError(t, c.Close(context.Background()))
+	assert.Same(t, os.Stderr, errorOutput())
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 2)
+	assert.Equal(t, "from fd 2", logs[0].Message)
+	assert.Equal(t, slog.LevelError, logs[0].Level)
+
+	attrs := attrsOf(logs[0])
+	assert.Equal(t, StreamStderr, attrs[StreamKey].String())
+	assert.Equal(t, int64(os.Getpid()), attrs[PIDKey].Int64())
+	assert.Equal(t, "unterminated", logs[1].Message)
+	assert.True(t, attrsOf(logs[1])[PartialKey].Bool())
+}
+
+func TestCaptureStderrInherited(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires unix")
+	}
+
+	c, err := CaptureStderr(&CaptureOptions{Logger: slog.New(&MockLogger{})})
+	require.NoError(t, err)
+
+	// The child inherits the redirected fd, keeping the pipe open
+	cmd := exec.Command("sleep", "10")
+	cmd.Stderr = os.Stderr
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	require.ErrorIs(t, c.Close(ctx), context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
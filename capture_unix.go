@@ -0,0 +1,89 @@
+//go:build unix
+
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// CaptureStderr redirects file descriptor 2 of the process into records with
+// the stream and pid attributes, capturing what cgo libraries and os.Stderr
+// write. The logger must not write to stderr, or its own output would be
+// captured again. Close restores the file descriptor.
+//
+// The Go runtime writes the traces of fatal errors and unrecovered panics to
+// fd 2 as well, so they are captured too. As the process dies with them, the
+// lines still in the capture pipe, in an async queue or in a buffering sink
+// are lost. Use a synchronous logger for the capture, and debug.SetCrashOutput
+// to keep a copy of crash traces in a file.
+func CaptureStderr(opts *CaptureOptions) (*FDCapture, error) {
+	if opts == nil {
+		opts = &CaptureOptions{}
+	}
+	return captureFD(unix.Stderr, StreamStderr, opts.StderrLevel, opts)
+}
+
+// captureFD redirects fd to a pipe read by a LineWriter
+func captureFD(fd int, stream string, level slog.Level, opts *CaptureOptions) (*FDCapture, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("create pipe: %w", err)
+	}
+
+	saved, err := unix.Dup(fd)
+	if err != nil {
+		_ = r.Close()
+		_ = w.Close()
+		return nil, fmt.Errorf("duplicate fd %d: %w", fd, err)
+	}
+
+	if err := unix.Dup2(int(w.Fd()), fd); err != nil {
+		_ = r.Close()
+		_ = w.Close()
+		_ = unix.Close(saved)
+		return nil, fmt.Errorf("redirect fd %d: %w", fd, err)
+	}
+	// The redirected fd keeps the write end open
+	_ = w.Close()
+
+	// The errors of the package itself are reported to the original stderr
+	var orig *os.File
+	if fd == unix.Stderr {
+		if dup, err := unix.Dup(saved); err == nil {
+			orig = os.NewFile(uintptr(dup), "/dev/stderr")
+			setCapturedStderr(orig)
+		}
+	}
+
+	attrs := append([]slog.Attr{
+		slog.String(StreamKey, stream),
+		slog.Int(PIDKey, os.Getpid()),
+	}, opts.Attrs...)
+
+	c := &FDCapture{
+		writer: NewLineWriter(opts.logger(), level, opts.maxLine(), attrs...),
+		reader: r,
+		done:   make(chan struct{}),
+		restore: func() error {
+			if orig != nil {
+				setCapturedStderr(nil)
+				_ = orig.Close()
+			}
+
+			// Replacing the fd closes the write end, ending the copy
+			err := unix.Dup2(saved, fd)
+			_ = unix.Close(saved)
+			if err != nil {
+				return fmt.Errorf("restore fd %d: %w", fd, err)
+			}
+			return nil
+		},
+	}
+	go c.copyLines(r)
+
+	return c, nil
+}
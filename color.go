@@ -97,12 +97,12 @@ func (h *ColoredHandler) Handle(_ context.Context, r slog.Record) error {
 
 		// Add handler attributes
 		for _, attr := range h.attrs {
-			fmt.Fprintf(h.w, "      %s%s%s: %v\n", cyan, attr.Key, reset, attr.Value.Any())
+			h.writeAttr(attr)
 		}
 
 		// Add record attributes
 		r.Attrs(func(attr slog.Attr) bool {
-			fmt.Fprintf(h.w, "      %s%s%s: %v\n", cyan, attr.Key, reset, attr.Value.Any())
+			h.writeAttr(attr)
 			return true
 		})
 	}
@@ -117,7 +117,7 @@ func (h *ColoredHandler) handleJSON(r *slog.Record) error {
 
 	// Add standard fields
 	m["time"] = r.Time.Format(time.RFC3339)
-	m["level"] = r.Level.String()
+	m["level"] = replaceLevelName(nil, slog.Any(slog.LevelKey, r.Level)).Value.String()
 	m["msg"] = r.Message
 
 	// Add source if enabled
@@ -131,12 +131,12 @@ func (h *ColoredHandler) handleJSON(r *slog.Record) error {
 
 	// Add handler attributes
 	for _, attr := range h.attrs {
-		m[attr.Key] = attr.Value.Any()
+		m[attr.Key] = attrValue(attr.Value)
 	}
 
 	// Add record attributes
 	r.Attrs(func(attr slog.Attr) bool {
-		m[attr.Key] = attr.Value.Any()
+		m[attr.Key] = attrValue(attr.Value)
 		return true
 	})
 
@@ -191,16 +191,130 @@ func (h *ColoredHandler) WithGroup(name string) slog.Handler {
 	return h2
 }
 
+// writeAttr writes an attribute in console format, rendering errors logged
+// with Err as a report of their chain, fields and stack
+func (h *ColoredHandler) writeAttr(attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		if report, ok := errorReport(value.Group()); ok {
+			fmt.Fprintf(h.w, "      %s%s%s: ", cyan, attr.Key, reset)
+			h.writeErrorReport(report)
+			return
+		}
+	}
+
+	fmt.Fprintf(h.w, "      %s%s%s: %v\n", cyan, attr.Key, reset, attrValue(value))
+}
+
+// errorReport returns the attributes of a group created by Err by key,
+// reporting whether the group is one. Such groups are recognized by the type
+// of their ErrorTypeKey value, so that other groups with the same keys are not.
+func errorReport(group []slog.Attr) (map[string]slog.Value, bool) {
+	report := make(map[string]slog.Value, len(group))
+	for _, attr := range group {
+		report[attr.Key] = attr.Value
+	}
+
+	typ, ok := report[ErrorTypeKey]
+	if !ok || typ.Kind() != slog.KindAny {
+		return nil, false
+	}
+	_, ok = typ.Any().(errorTypeName)
+	return report, ok
+}
+
+// writeErrorReport writes the message, type, chain, fields and stack of an error
+func (h *ColoredHandler) writeErrorReport(report map[string]slog.Value) {
+	fmt.Fprintf(h.w, "%s%s%s %s(%s)%s\n", lightRed, report[ErrorMessageKey], reset,
+		darkGray, report[ErrorTypeKey], reset)
+
+	if chain, ok := report[ErrorChainKey].Any().([]map[string]any); ok {
+		h.writeErrorChain(chain, "        ")
+	}
+	if branches, ok := report[ErrorBranchesKey].Any().([][]map[string]any); ok {
+		h.writeErrorBranches(branches, "        ")
+	}
+
+	if fields, ok := report[ErrorFieldsKey]; ok && fields.Kind() == slog.KindGroup {
+		for _, field := range fields.Group() {
+			fmt.Fprintf(h.w, "        %s%s%s: %v\n", cyan, field.Key, reset, attrValue(field.Value))
+		}
+	}
+
+	if stack, ok := report[ErrorStackKey].Any().([]string); ok {
+		fmt.Fprintf(h.w, "        %sStack:%s\n", darkGray, reset)
+		for _, frame := range stack {
+			fmt.Fprintf(h.w, "          %s%s%s\n", darkGray, frame, reset)
+		}
+	}
+}
+
+// writeErrorChain writes the wrapped errors of a chain, one per line
+func (h *ColoredHandler) writeErrorChain(chain []map[string]any, indent string) {
+	for _, entry := range chain {
+		fmt.Fprintf(h.w, "%s%scaused by:%s %v %s(%v)%s\n", indent, darkGray, reset,
+			entry[ErrorMessageKey], darkGray, entry[ErrorTypeKey], reset)
+		if branches, ok := entry[ErrorBranchesKey].([][]map[string]any); ok {
+			h.writeErrorBranches(branches, indent+"  ")
+		}
+	}
+}
+
+// writeErrorBranches writes the chains of the errors joined by an error
+func (h *ColoredHandler) writeErrorBranches(branches [][]map[string]any, indent string) {
+	for i, branch := range branches {
+		if len(branch) == 0 {
+			continue
+		}
+		fmt.Fprintf(h.w, "%s%s[%d]%s %v %s(%v)%s\n", indent, darkGray, i+1, reset,
+			branch[0][ErrorMessageKey], darkGray, branch[0][ErrorTypeKey], reset)
+		h.writeErrorChain(branch[1:], indent+"  ")
+		if nested, ok := branch[0][ErrorBranchesKey].([][]map[string]any); ok {
+			h.writeErrorBranches(nested, indent+"  ")
+		}
+	}
+}
+
+// attrValue resolves LogValuers and converts groups to maps for output
+func attrValue(v slog.Value) any {
+	v = v.Resolve()
+	if v.Kind() != slog.KindGroup {
+		return v.Any()
+	}
+
+	group := v.Group()
+	m := make(map[string]any, len(group))
+	for _, attr := range group {
+		value := attrValue(attr.Value)
+
+		// Inline groups with an empty key into the parent
+		if inner, ok := value.(map[string]any); ok && attr.Key == "" {
+			for k, v := range inner {
+				m[k] = v
+			}
+			continue
+		}
+		m[attr.Key] = value
+	}
+	return m
+}
+
 // getLevelColor returns the color for the given level
 func getLevelColor(level slog.Level) (colorCode, levelText string) {
 	switch {
+	case level >= LevelFatal:
+		return magenta, "FATAL"
+	case level >= LevelPanic:
+		return magenta, "PANIC"
 	case level >= slog.LevelError:
 		return red, "ERROR"
 	case level >= slog.LevelWarn:
 		return yellow, "WARN "
 	case level >= slog.LevelInfo:
 		return green, "INFO "
-	default:
+	case level >= slog.LevelDebug:
 		return blue, "DEBUG"
+	default:
+		return darkGray, "TRACE"
 	}
 }
@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// ContextExtractor extracts log attributes from a context
+type ContextExtractor func(ctx context.Context) []slog.Attr
+
+var (
+	// extractors holds the globally registered context extractors
+	extractors []ContextExtractor
+
+	// extractorsMu protects extractors
+	extractorsMu sync.RWMutex
+)
+
+// contextAttrsKey is the context key for attributes added with ContextWithAttrs
+type contextAttrsKey struct{}
+
+// RegisterContextExtractor registers an extractor that is applied to every record
+// logged with a context through a ContextHandler
+func RegisterContextExtractor(fn ContextExtractor) {
+	if fn == nil {
+		return
+	}
+
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors = append(extractors, fn)
+}
+
+// registeredExtractors returns a snapshot of the globally registered extractors
+func registeredExtractors() []ContextExtractor {
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+	return extractors[:len(extractors):len(extractors)]
+}
+
+// ContextWithAttrs returns a copy of ctx carrying the given attributes,
+// which a ContextHandler adds to every record logged with the context
+func ContextWithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+
+	existing := AttrsFromContext(ctx)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	merged = append(merged, existing...)
+	merged = append(merged, attrs...)
+
+	return context.WithValue(ctx, contextAttrsKey{}, merged)
+}
+
+// AttrsFromContext returns the attributes added to ctx with ContextWithAttrs
+func AttrsFromContext(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+
+	attrs, _ := ctx.Value(contextAttrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// ContextHandler is a slog.Handler that adds attributes extracted from the
+// context to each record before passing it to the next handler.
+type ContextHandler struct {
+	next       slog.Handler
+	extractors []ContextExtractor
+}
+
+// NewContextHandler creates a new ContextHandler wrapping next.
+// The given extractors run after the attributes from ContextWithAttrs and
+// before the globally registered ones.
+func NewContextHandler(next slog.Handler, extractors ...ContextExtractor) *ContextHandler {
+	return &ContextHandler{
+		next:       next,
+		extractors: extractors,
+	}
+}
+
+// Enabled implements slog.Handler.
+func (h *ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+//
+//nolint:gocritic // Cannot change signature due to interface contract
+func (h *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		r = r.Clone()
+		r.AddAttrs(AttrsFromContext(ctx)...)
+
+		for _, extract := range h.extractors {
+			r.AddAttrs(extract(ctx)...)
+		}
+
+		for _, extract := range registeredExtractors() {
+			r.AddAttrs(extract(ctx)...)
+		}
+	}
+
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{
+		next:       h.next.WithAttrs(attrs),
+		extractors: h.extractors,
+	}
+}
+
+// WithGroup implements slog.Handler.
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{
+		next:       h.next.WithGroup(name),
+		extractors: h.extractors,
+	}
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type requestIDKey struct{}
+
+func TestContextHandler(t *testing.T) {
+	mock := &MockLogger{}
+	log := slog.New(NewContextHandler(mock, func(ctx context.Context) []slog.Attr {
+		if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+			return []slog.Attr{slog.String("request_id", id)}
+		}
+		return nil
+	}))
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-1")
+	ctx = ContextWithAttrs(ctx, slog.String("tenant_id", "acme"))
+
+	log.InfoContext(ctx, "with context", "key", "value")
+	log.Info("without context")
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 2)
+	assert.Equal(t, []slog.Attr{
+		slog.String("key", "value"),
+		slog.String("tenant_id", "acme"),
+		slog.String("request_id", "req-1"),
+	}, logs[0].Attrs)
+	assert.Empty(t, logs[1].Attrs)
+}
+
+func TestRegisterContextExtractor(t *testing.T) {
+	extractorsMu.Lock()
+	saved := extractors
+	extractorsMu.Unlock()
+	t.Cleanup(func() {
+		extractorsMu.Lock()
+		extractors = saved
+		extractorsMu.Unlock()
+	})
+
+	RegisterContextExtractor(func(ctx context.Context) []slog.Attr {
+		if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+			return []slog.Attr{slog.String("request_id", id)}
+		}
+		return nil
+	})
+
+	mock := &MockLogger{}
+	log := slog.New(NewContextHandler(mock))
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-2")
+	log.InfoContext(ctx, "registered extractor")
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 1)
+	assert.Contains(t, logs[0].Attrs, slog.String("request_id", "req-2"))
+}
+
+func TestContextWithAttrs(t *testing.T) {
+	ctx := ContextWithAttrs(context.Background(), slog.String("a", "1"))
+	child := ContextWithAttrs(ctx, slog.String("b", "2"))
+
+	assert.Equal(t, []slog.Attr{slog.String("a", "1")}, AttrsFromContext(ctx))
+	assert.Equal(t, []slog.Attr{slog.String("a", "1"), slog.String("b", "2")}, AttrsFromContext(child))
+	assert.Equal(t, ctx, ContextWithAttrs(ctx))
+}
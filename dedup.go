@@ -0,0 +1,301 @@
+package logger
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Dedup attribute keys
+const (
+	DedupCountKey   = "repeated"
+	DedupMessageKey = "original_msg"
+	DedupFirstKey   = "first_seen"
+	DedupLastKey    = "last_seen"
+)
+
+// Dedup defaults
+const (
+	DefaultDedupWindow     = 5 * time.Second
+	DefaultDedupMaxEntries = 1024
+)
+
+// DedupOptions configures a DedupHandler
+type DedupOptions struct {
+	// Window is how long identical records are collapsed, defaults to DefaultDedupWindow
+	Window time.Duration
+
+	// Keys are attribute keys whose values are part of the fingerprint, so
+	// records that differ in them are not collapsed
+	Keys []string
+
+	// MaxEntries bounds the number of tracked fingerprints, defaults to DefaultDedupMaxEntries
+	MaxEntries int
+}
+
+// dedupEntry tracks the suppressed repeats of one fingerprint
+type dedupEntry struct {
+	fingerprint uint64
+	handler     slog.Handler
+	level       slog.Level
+	message     string
+	pc          uintptr
+	first       time.Time
+	last        time.Time
+	expires     time.Time
+	suppressed  int
+}
+
+// dedupState holds the LRU of fingerprints shared by derived handlers
+type dedupState struct {
+	opts      DedupOptions
+	keys      []string
+	mu        sync.Mutex
+	lru       *list.List
+	entries   map[uint64]*list.Element
+	nextSweep time.Time
+	now       func() time.Time
+
+	// timer emits the summaries of expired entries when no record arrives
+	timer *time.Timer
+}
+
+// DedupHandler is a slog.Handler that collapses identical records within a
+// time window and emits a summary record with the number of repeats.
+// Records logged through handlers with different attributes or groups are
+// not collapsed. Summaries are emitted when the window of their record has
+// passed, or on Flush.
+type DedupHandler struct {
+	next  slog.Handler
+	state *dedupState
+
+	// scope hashes the attributes and groups added with WithAttrs and WithGroup
+	scope uint64
+}
+
+// NewDedupHandler creates a new DedupHandler wrapping next.
+func NewDedupHandler(next slog.Handler, opts *DedupOptions) *DedupHandler {
+	if opts == nil {
+		opts = &DedupOptions{}
+	}
+
+	state := &dedupState{
+		opts:    *opts,
+		lru:     list.New(),
+		entries: make(map[uint64]*list.Element),
+		now:     time.Now,
+	}
+	if state.opts.Window <= 0 {
+		state.opts.Window = DefaultDedupWindow
+	}
+	if state.opts.MaxEntries <= 0 {
+		state.opts.MaxEntries = DefaultDedupMaxEntries
+	}
+	state.keys = append([]string(nil), opts.Keys...)
+	sort.Strings(state.keys)
+
+	return &DedupHandler{next: next, state: state}
+}
+
+// Enabled implements slog.Handler.
+func (h *DedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+//
+//nolint:gocritic // Cannot change signature due to interface contract
+func (h *DedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	s := h.state
+	now := s.now()
+	fp := s.fingerprint(h.scope, &r)
+
+	s.mu.Lock()
+
+	// Collect summaries of expired and evicted entries to emit outside the lock
+	summaries := s.expire(now, fp)
+
+	if el, ok := s.entries[fp]; ok {
+		entry := el.Value.(*dedupEntry)
+		entry.suppressed++
+		entry.last = now
+		s.lru.MoveToFront(el)
+		if s.timer == nil {
+			s.schedule(entry.expires.Sub(now))
+		}
+		s.mu.Unlock()
+		return s.emit(summaries)
+	}
+
+	s.entries[fp] = s.lru.PushFront(&dedupEntry{
+		fingerprint: fp,
+		handler:     h.next,
+		level:       r.Level,
+		message:     r.Message,
+		pc:          r.PC,
+		first:       now,
+		last:        now,
+		expires:     now.Add(s.opts.Window),
+	})
+	for s.lru.Len() > s.opts.MaxEntries {
+		if summary := s.remove(s.lru.Back()); summary != nil {
+			summaries = append(summaries, summary)
+		}
+	}
+	s.mu.Unlock()
+
+	return errors.Join(s.emit(summaries), h.next.Handle(ctx, r))
+}
+
+// WithAttrs implements slog.Handler.
+func (h *DedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	hash := fnv.New64a()
+	_, _ = fmt.Fprintf(hash, "%d", h.scope)
+	for _, attr := range attrs {
+		_, _ = fmt.Fprintf(hash, "\x00a%s=%s", attr.Key, attr.Value.Resolve().String())
+	}
+	return &DedupHandler{next: h.next.WithAttrs(attrs), state: h.state, scope: hash.Sum64()}
+}
+
+// WithGroup implements slog.Handler.
+func (h *DedupHandler) WithGroup(name string) slog.Handler {
+	hash := fnv.New64a()
+	_, _ = fmt.Fprintf(hash, "%d\x00g%s", h.scope, name)
+	return &DedupHandler{next: h.next.WithGroup(name), state: h.state, scope: hash.Sum64()}
+}
+
+// Flush emits the summaries of all pending repeats
+func (h *DedupHandler) Flush(context.Context) error {
+	s := h.state
+
+	s.mu.Lock()
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	var summaries []*dedupEntry
+	for s.lru.Len() > 0 {
+		if summary := s.remove(s.lru.Back()); summary != nil {
+			summaries = append(summaries, summary)
+		}
+	}
+	s.mu.Unlock()
+
+	return s.emit(summaries)
+}
+
+// fingerprint hashes the handler scope and the level, message and
+// configured key values of r
+func (s *dedupState) fingerprint(scope uint64, r *slog.Record) uint64 {
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%d\x00%d\x00%s", scope, r.Level, r.Message)
+
+	if len(s.keys) > 0 {
+		values := make(map[string]string, len(s.keys))
+		r.Attrs(func(attr slog.Attr) bool {
+			values[attr.Key] = attr.Value.Resolve().String()
+			return true
+		})
+		for _, key := range s.keys {
+			_, _ = fmt.Fprintf(h, "\x00%s=%s", key, values[key])
+		}
+	}
+
+	return h.Sum64()
+}
+
+// schedule arms the timer emitting the summaries of expired entries.
+// It must be called with the lock held.
+func (s *dedupState) schedule(d time.Duration) {
+	s.timer = time.AfterFunc(max(d, 0), s.tick)
+}
+
+// tick emits the summaries of the expired entries and schedules the next
+// tick while entries with repeats are pending
+func (s *dedupState) tick() {
+	s.mu.Lock()
+	s.timer = nil
+	now := s.now()
+	s.nextSweep = time.Time{}
+	summaries := s.expire(now, 0)
+
+	var next time.Time
+	for el := s.lru.Front(); el != nil; el = el.Next() {
+		if entry := el.Value.(*dedupEntry); entry.suppressed > 0 && (next.IsZero() || entry.expires.Before(next)) {
+			next = entry.expires
+		}
+	}
+	if !next.IsZero() {
+		s.schedule(next.Sub(now))
+	}
+	s.mu.Unlock()
+
+	_ = s.emit(summaries)
+}
+
+// expire removes the entries whose window has passed, least recently used
+// first. The full scan runs at most four times per window, while the entry
+// of the record being handled is always checked.
+// It must be called with the lock held.
+func (s *dedupState) expire(now time.Time, fp uint64) []*dedupEntry {
+	var summaries []*dedupEntry
+
+	if now.Before(s.nextSweep) {
+		if el, ok := s.entries[fp]; ok && !now.Before(el.Value.(*dedupEntry).expires) {
+			if summary := s.remove(el); summary != nil {
+				summaries = append(summaries, summary)
+			}
+		}
+		return summaries
+	}
+	s.nextSweep = now.Add(s.opts.Window / 4)
+
+	for el := s.lru.Back(); el != nil; {
+		prev := el.Prev()
+		if entry := el.Value.(*dedupEntry); !now.Before(entry.expires) {
+			if summary := s.remove(el); summary != nil {
+				summaries = append(summaries, summary)
+			}
+		}
+		el = prev
+	}
+	return summaries
+}
+
+// remove drops an entry, returning it if it has repeats to summarize.
+// It must be called with the lock held.
+func (s *dedupState) remove(el *list.Element) *dedupEntry {
+	entry := s.lru.Remove(el).(*dedupEntry)
+	delete(s.entries, entry.fingerprint)
+
+	if entry.suppressed == 0 {
+		return nil
+	}
+	return entry
+}
+
+// emit writes a summary record for each entry
+func (s *dedupState) emit(entries []*dedupEntry) error {
+	var errs []error
+	for _, entry := range entries {
+		r := slog.NewRecord(entry.last, entry.level,
+			fmt.Sprintf("previous message repeated %d times", entry.suppressed), entry.pc)
+		r.AddAttrs(
+			slog.String(DedupMessageKey, entry.message),
+			slog.Int(DedupCountKey, entry.suppressed),
+			slog.Time(DedupFirstKey, entry.first),
+			slog.Time(DedupLastKey, entry.last),
+		)
+
+		if err := entry.handler.Handle(context.Background(), r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDedupHandler(opts *DedupOptions) (*DedupHandler, *MockLogger, *time.Time) {
+	mock := &MockLogger{}
+	handler := NewDedupHandler(mock, opts)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	handler.state.now = func() time.Time { return now }
+	return handler, mock, &now
+}
+
+func TestDedupHandler(t *testing.T) {
+	handler, mock, now := newTestDedupHandler(&DedupOptions{Window: time.Second})
+	log := slog.New(handler)
+
+	for range 5 {
+		log.Error("connection refused")
+		*now = now.Add(100 * time.Millisecond)
+	}
+	log.Error("other error")
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 2)
+	assert.Equal(t, "connection refused", logs[0].Message)
+	assert.Equal(t, "other error", logs[1].Message)
+
+	// The next repeat after the window starts a new one and emits the summary
+	*now = now.Add(time.Second)
+	log.Error("connection refused")
+
+	logs = mock.GetLogs()
+	require.Len(t, logs, 4)
+	assert.Equal(t, "previous message repeated 4 times", logs[2].Message)
+	assert.Equal(t, slog.LevelError, logs[2].Level)
+
+	attrs := make(map[string]slog.Value)
+	for _, attr := range logs[2].Attrs {
+		attrs[attr.Key] = attr.Value
+	}
+	assert.Equal(t, "connection refused", attrs[DedupMessageKey].String())
+	assert.Equal(t, int64(4), attrs[DedupCountKey].Int64())
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), attrs[DedupFirstKey].Time())
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 400*int(time.Millisecond), time.UTC), attrs[DedupLastKey].Time())
+
+	// "other error" had no repeats, so it expires without a summary
+	assert.Equal(t, "connection refused", logs[3].Message)
+}
+
+func TestDedupHandlerKeys(t *testing.T) {
+	handler, mock, _ := newTestDedupHandler(&DedupOptions{Keys: []string{"host"}})
+	log := slog.New(handler)
+
+	log.Warn("slow", "host", "a", "took", 1)
+	log.Warn("slow", "host", "a", "took", 2)
+	log.Warn("slow", "host", "b", "took", 3)
+
+	assert.Len(t, mock.GetLogs(), 2)
+}
+
+func TestDedupHandlerFlushAndEviction(t *testing.T) {
+	handler, mock, _ := newTestDedupHandler(&DedupOptions{MaxEntries: 1})
+	log := slog.New(handler)
+
+	log.Info("a")
+	log.Info("a")
+	log.Info("b")
+	log.Info("b")
+	log.Info("b")
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 3)
+	assert.Equal(t, "previous message repeated 1 times", logs[1].Message)
+
+	require.NoError(t, handler.Flush(context.Background()))
+	logs = mock.GetLogs()
+	require.Len(t, logs, 4)
+	assert.Equal(t, "previous message repeated 2 times", logs[3].Message)
+}
+
+func TestDedupHandlerScope(t *testing.T) {
+	handler, mock, _ := newTestDedupHandler(nil)
+	log := slog.New(handler)
+
+	a := log.With("component", "a")
+	b := log.With("component", "b")
+	a.Warn("retrying")
+	b.Warn("retrying")
+	log.WithGroup("g").Warn("retrying")
+	log.With("component", "a").Warn("retrying")
+	a.Warn("retrying")
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 3)
+	assert.Equal(t, []slog.Attr{slog.String("component", "a")}, logs[0].Attrs)
+	assert.Equal(t, []slog.Attr{slog.String("component", "b")}, logs[1].Attrs)
+
+	// The summary is written through the handler of the collapsed records
+	require.NoError(t, handler.Flush(context.Background()))
+	logs = mock.GetLogs()
+	require.Len(t, logs, 4)
+	assert.Equal(t, "previous message repeated 2 times", logs[3].Message)
+	assert.Equal(t, slog.String("component", "a"), logs[3].Attrs[0])
+}
+
+func TestDedupHandlerTimer(t *testing.T) {
+	mock := &MockLogger{}
+	log := slog.New(NewDedupHandler(mock, &DedupOptions{Window: 20 * time.Millisecond}))
+
+	for range 3 {
+		log.Error("storm")
+	}
+
+	// The summary is emitted once the window passes without another record
+	assert.Eventually(t, func() bool { return len(mock.GetLogs()) == 2 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, "previous message repeated 2 times", mock.GetLogs()[1].Message)
+}
@@ -0,0 +1,265 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"reflect"
+	"runtime"
+	"slices"
+	"sync/atomic"
+)
+
+// ErrorKey is the key of the attribute returned by Err
+const ErrorKey = "error"
+
+// Error attribute keys, inside the ErrorKey group
+const (
+	ErrorMessageKey  = "message"
+	ErrorTypeKey     = "type"
+	ErrorChainKey    = "chain"
+	ErrorBranchesKey = "branches"
+	ErrorFieldsKey   = "fields"
+	ErrorStackKey    = "stack"
+)
+
+// Error chain limits
+const (
+	maxErrorDepth  = 32
+	maxStackFrames = 32
+)
+
+// ErrorFielder is implemented by errors that expose structured fields
+type ErrorFielder interface {
+	// Fields returns the fields logged with the error
+	Fields() map[string]any
+}
+
+// StackTracer is implemented by errors that capture the stack they were created at
+type StackTracer interface {
+	// StackTrace returns the program counters of the stack
+	StackTrace() []uintptr
+}
+
+// noErrorStacks disables capturing the call site stack in Err
+var noErrorStacks atomic.Bool
+
+// SetErrorStacks enables or disables capturing the call site stack in Err for
+// errors that carry no stack of their own. It is enabled by default, and Init
+// sets it from Config.EnableStacktrace.
+func SetErrorStacks(enabled bool) {
+	noErrorStacks.Store(!enabled)
+}
+
+// Err returns an attribute logging err as a group with its message, Go type,
+// unwrapped chain including errors.Join branches, the fields of errors
+// implementing ErrorFielder or slog.LogValuer, and the stack captured by the
+// error or, if it has none, at the call site. A nil error returns an empty
+// attribute, which handlers ignore.
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+
+	v := errorValue{err: err}
+	if !noErrorStacks.Load() && errorStack(err) == nil {
+		pcs := make([]uintptr, maxStackFrames)
+		v.pcs = pcs[:runtime.Callers(2, pcs)]
+	}
+
+	return slog.Any(ErrorKey, v)
+}
+
+// ErrValue returns the value of the Err attribute without the call site
+// stack, for adapters logging errors passed to another logging API where
+// the call site is not the caller of ErrValue
+func ErrValue(err error) slog.Value {
+	return slog.AnyValue(errorValue{err: err})
+}
+
+// errorValue implements slog.LogValuer for Err, building the group when the
+// record is handled
+type errorValue struct {
+	err error
+	pcs []uintptr
+}
+
+// errorTypeName is the value of ErrorTypeKey in the groups built by Err,
+// identifying them to handlers after the LogValuer has been resolved
+type errorTypeName string
+
+// LogValue implements slog.LogValuer.
+func (v errorValue) LogValue() slog.Value {
+	chain := errorChain(v.err, 0)
+	attrs := []slog.Attr{
+		slog.String(ErrorMessageKey, v.err.Error()),
+		slog.Any(ErrorTypeKey, errorTypeName(fmt.Sprintf("%T", v.err))),
+	}
+	if branches, ok := chain[0][ErrorBranchesKey]; ok {
+		attrs = append(attrs, slog.Any(ErrorBranchesKey, branches))
+	}
+	if len(chain) > 1 {
+		attrs = append(attrs, slog.Any(ErrorChainKey, chain[1:]))
+	}
+
+	if fields := errorFields(v.err); len(fields) > 0 {
+		attrs = append(attrs, slog.Attr{Key: ErrorFieldsKey, Value: slog.GroupValue(fields...)})
+	}
+
+	pcs := errorStack(v.err)
+	if pcs == nil {
+		pcs = v.pcs
+	}
+	if stack := formatStack(pcs); len(stack) > 0 {
+		attrs = append(attrs, slog.Any(ErrorStackKey, stack))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// unwrapErrors returns the errors wrapped by err
+func unwrapErrors(err error) []error {
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		return u.Unwrap()
+	case interface{ Unwrap() error }:
+		if inner := u.Unwrap(); inner != nil {
+			return []error{inner}
+		}
+	}
+	return nil
+}
+
+// errorChain describes err and the errors it wraps, outermost first. An
+// error wrapping several errors, like errors.Join, ends the chain and lists
+// the chain of each wrapped error as its branches.
+func errorChain(err error, depth int) []map[string]any {
+	var chain []map[string]any
+	for err != nil && depth < maxErrorDepth {
+		entry := map[string]any{
+			ErrorMessageKey: err.Error(),
+			ErrorTypeKey:    fmt.Sprintf("%T", err),
+		}
+		chain = append(chain, entry)
+
+		errs := unwrapErrors(err)
+		if len(errs) > 1 {
+			branches := make([][]map[string]any, 0, len(errs))
+			for _, inner := range errs {
+				if inner != nil {
+					branches = append(branches, errorChain(inner, depth+1))
+				}
+			}
+			entry[ErrorBranchesKey] = branches
+			break
+		}
+		if len(errs) == 0 {
+			break
+		}
+
+		err = errs[0]
+		depth++
+	}
+	return chain
+}
+
+// errorFields collects the fields of the errors in the chain. Fields of outer
+// errors take precedence over fields of the errors they wrap.
+func errorFields(err error) []slog.Attr {
+	fields := make(map[string]slog.Value)
+	var walk func(err error, depth int)
+	walk = func(err error, depth int) {
+		if err == nil || depth >= maxErrorDepth {
+			return
+		}
+
+		// Visit the wrapped errors first so outer errors overwrite their fields
+		for _, inner := range unwrapErrors(err) {
+			walk(inner, depth+1)
+		}
+
+		switch e := err.(type) {
+		case ErrorFielder:
+			for k, v := range e.Fields() {
+				fields[k] = slog.AnyValue(v)
+			}
+		case slog.LogValuer:
+			value := e.LogValue().Resolve()
+			if value.Kind() != slog.KindGroup {
+				fields["value"] = value
+				return
+			}
+			for _, attr := range value.Group() {
+				fields[attr.Key] = attr.Value
+			}
+		}
+	}
+	walk(err, 0)
+
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, k := range slices.Sorted(maps.Keys(fields)) {
+		attrs = append(attrs, slog.Attr{Key: k, Value: fields[k]})
+	}
+	return attrs
+}
+
+// errorStack returns the innermost stack captured by an error in the chain.
+// Besides StackTracer, errors with a StackTrace method returning a slice of
+// uintptr-based frames, like github.com/pkg/errors, are supported.
+func errorStack(err error) []uintptr {
+	var pcs []uintptr
+	for depth := 0; err != nil && depth < maxErrorDepth; depth++ {
+		if stack := stackOf(err); stack != nil {
+			pcs = stack
+		}
+
+		errs := unwrapErrors(err)
+		if len(errs) != 1 {
+			break
+		}
+		err = errs[0]
+	}
+	return pcs
+}
+
+// stackOf returns the stack captured by err itself
+func stackOf(err error) []uintptr {
+	if st, ok := err.(StackTracer); ok {
+		return st.StackTrace()
+	}
+
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return nil
+	}
+	if t := m.Type().Out(0); t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	frames := m.Call(nil)[0]
+	pcs := make([]uintptr, frames.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(frames.Index(i).Uint())
+	}
+	return pcs
+}
+
+// formatStack formats program counters as "function file:line" frames
+func formatStack(pcs []uintptr) []string {
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	stack := make([]string, 0, len(pcs))
+	frames := runtime.CallersFrames(pcs)
+	for len(stack) < maxStackFrames {
+		f, more := frames.Next()
+		if f.Function != "" || f.File != "" {
+			stack = append(stack, fmt.Sprintf("%s %s:%d", f.Function, f.File, f.Line))
+		}
+		if !more {
+			break
+		}
+	}
+	return stack
+}
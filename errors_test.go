@@ -0,0 +1,215 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// queryError exposes structured fields
+type queryError struct {
+	table string
+	err   error
+}
+
+func (e *queryError) Error() string          { return "query " + e.table + ": " + e.err.Error() }
+func (e *queryError) Unwrap() error          { return e.err }
+func (e *queryError) Fields() map[string]any { return map[string]any{"table": e.table} }
+
+// valuedError implements slog.LogValuer
+type valuedError struct{}
+
+func (valuedError) Error() string { return "valued" }
+func (valuedError) LogValue() slog.Value {
+	return slog.GroupValue(slog.Int("code", 42), slog.String("table", "inner"))
+}
+
+// stackError captures the stack it was created at
+type stackError struct {
+	pcs []uintptr
+}
+
+func newStackError() *stackError {
+	pcs := make([]uintptr, 8)
+	return &stackError{pcs: pcs[:runtime.Callers(1, pcs)]}
+}
+
+func (*stackError) Error() string           { return "with stack" }
+func (e *stackError) StackTrace() []uintptr { return e.pcs }
+
+// pkgStackError mimics github.com/pkg/errors, returning frames of a named uintptr type
+type (
+	pkgFrame      uintptr
+	pkgTrace      []pkgFrame
+	pkgStackError struct{ frames pkgTrace }
+)
+
+func (*pkgStackError) Error() string          { return "pkg stack" }
+func (e *pkgStackError) StackTrace() pkgTrace { return e.frames }
+
+func errJSON(t *testing.T, err error) map[string]any {
+	t.Helper()
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", Err(err))
+
+	var out map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	group, ok := out[ErrorKey].(map[string]any)
+	require.True(t, ok, "error should be a nested object: %s", buf.String())
+	return group
+}
+
+func TestErr(t *testing.T) {
+	base := &fs.PathError{Op: "open", Path: "/etc/app", Err: fs.ErrNotExist}
+	err := fmt.Errorf("load config: %w", &queryError{table: "users", err: base})
+
+	group := errJSON(t, err)
+	assert.Equal(t, err.Error(), group[ErrorMessageKey])
+	assert.Equal(t, "*fmt.wrapError", group[ErrorTypeKey])
+
+	chain, ok := group[ErrorChainKey].([]any)
+	require.True(t, ok)
+	require.Len(t, chain, 3)
+	assert.Equal(t, "*logger.queryError", chain[0].(map[string]any)[ErrorTypeKey])
+	assert.Equal(t, "*fs.PathError", chain[1].(map[string]any)[ErrorTypeKey])
+	assert.Equal(t, fs.ErrNotExist.Error(), chain[2].(map[string]any)[ErrorMessageKey])
+
+	assert.Equal(t, map[string]any{"table": "users"}, group[ErrorFieldsKey])
+
+	// Errors without a stack get the call site stack
+	stack, ok := group[ErrorStackKey].([]any)
+	require.True(t, ok)
+	require.NotEmpty(t, stack)
+	assert.Contains(t, stack[0], "logger.errJSON")
+}
+
+func TestErrJoin(t *testing.T) {
+	err := fmt.Errorf("batch: %w", errors.Join(
+		errors.New("first"),
+		fmt.Errorf("second: %w", errors.New("cause")),
+	))
+
+	group := errJSON(t, err)
+	chain := group[ErrorChainKey].([]any)
+	require.Len(t, chain, 1)
+
+	joined := chain[0].(map[string]any)
+	assert.Equal(t, "*errors.joinError", joined[ErrorTypeKey])
+
+	branches, ok := joined[ErrorBranchesKey].([]any)
+	require.True(t, ok)
+	require.Len(t, branches, 2)
+	assert.Len(t, branches[0], 1)
+	second := branches[1].([]any)
+	require.Len(t, second, 2)
+	assert.Equal(t, "cause", second[1].(map[string]any)[ErrorMessageKey])
+
+	// A top level join lists its branches directly
+	group = errJSON(t, errors.Join(errors.New("a"), errors.New("b")))
+	assert.NotContains(t, group, ErrorChainKey)
+	assert.Len(t, group[ErrorBranchesKey], 2)
+}
+
+func TestErrFields(t *testing.T) {
+	err := &queryError{table: "outer", err: valuedError{}}
+
+	group := errJSON(t, err)
+	assert.Equal(t, map[string]any{"code": float64(42), "table": "outer"}, group[ErrorFieldsKey])
+}
+
+func TestErrStack(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", newStackError())
+
+	stack := errJSON(t, err)[ErrorStackKey].([]any)
+	require.NotEmpty(t, stack)
+	assert.Contains(t, stack[0], "logger.newStackError")
+
+	pcs := make([]uintptr, 1)
+	runtime.Callers(1, pcs)
+	pkgErr := &pkgStackError{frames: pkgTrace{pkgFrame(pcs[0])}}
+
+	stack = errJSON(t, pkgErr)[ErrorStackKey].([]any)
+	require.Len(t, stack, 1)
+	assert.Contains(t, stack[0], "logger.TestErrStack")
+
+	keepErrorStacks(t)
+	SetErrorStacks(false)
+	assert.NotContains(t, errJSON(t, errors.New("plain")), ErrorStackKey)
+}
+
+func TestErrNil(t *testing.T) {
+	assert.True(t, Err(nil).Equal(slog.Attr{}))
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("ok", Err(nil))
+	assert.NotContains(t, buf.String(), ErrorKey)
+}
+
+func TestErrValue(t *testing.T) {
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", slog.Any("cause", ErrValue(errors.New("plain"))))
+
+	var out map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	group, ok := out["cause"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "plain", group[ErrorMessageKey])
+	assert.NotContains(t, group, ErrorStackKey)
+
+	buf.Reset()
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", slog.Any("cause", ErrValue(newStackError())))
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	group, ok = out["cause"].(map[string]any)
+	require.True(t, ok)
+	assert.Contains(t, group, ErrorStackKey)
+}
+
+func TestColoredHandlerErr(t *testing.T) {
+	keepErrorStacks(t)
+	SetErrorStacks(false)
+
+	var buf bytes.Buffer
+	err := fmt.Errorf("request: %w", errors.Join(
+		&queryError{table: "users", err: errors.New("timeout")},
+		errors.New("rollback failed"),
+	))
+	slog.New(NewColoredHandler(&buf, nil, false)).Error("failed", Err(err))
+
+	assert.Contains(t, buf.String(), "error"+reset+": "+lightRed+"request: query users: timeout\nrollback failed")
+	assert.Contains(t, buf.String(), "(*fmt.wrapError)")
+	assert.Contains(t, buf.String(), "caused by:"+reset+" query users: timeout\nrollback failed")
+	assert.Contains(t, buf.String(), "[1]"+reset+" query users: timeout")
+	assert.Contains(t, buf.String(), "caused by:"+reset+" timeout")
+	assert.Contains(t, buf.String(), "[2]"+reset+" rollback failed")
+	assert.Contains(t, buf.String(), "table"+reset+": users")
+
+	// The JSON mode nests the error
+	buf.Reset()
+	slog.New(NewColoredHandler(&buf, nil, true)).Error("failed", Err(err))
+	assert.Contains(t, buf.String(), `"branches": [`)
+
+	// Other groups with the same keys are not error reports, even after the
+	// error group is resolved by a handler in front
+	buf.Reset()
+	log := slog.New(NewRedactHandler(NewColoredHandler(&buf, nil, false), &RedactOptions{}))
+	log.Info("clicked", slog.Group("event", "type", "click", "message", "hi"))
+	assert.NotContains(t, buf.String(), lightRed)
+	log.Error("failed", Err(errors.New("boom")))
+	assert.Contains(t, buf.String(), lightRed+"boom")
+}
+
+// keepErrorStacks restores the process-wide setting of SetErrorStacks, which
+// Init changes, when the test ends
+func keepErrorStacks(t *testing.T) {
+	enabled := !noErrorStacks.Load()
+	t.Cleanup(func() { SetErrorStacks(enabled) })
+}
@@ -0,0 +1,209 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+// BackfillKey is the attribute added to records replayed by a FlightRecorderHandler
+const BackfillKey = "backfill"
+
+// Flight recorder defaults
+const (
+	DefaultFlightRecorderSize           = 256
+	DefaultFlightRecorderPerContextSize = 64
+)
+
+// FlightRecorderOptions configures a FlightRecorderHandler
+type FlightRecorderOptions struct {
+	// Size is the number of records kept for logging without a request
+	// buffer, defaults to DefaultFlightRecorderSize
+	Size int
+
+	// MinLevel is the lowest level that is recorded, defaults to LevelTrace
+	MinLevel *slog.Level
+
+	// TriggerLevel is the level that replays the buffered records, defaults to LevelError
+	TriggerLevel *slog.Level
+}
+
+// flightEntry is a buffered record with the handler it is replayed to
+type flightEntry struct {
+	ctx     context.Context
+	handler slog.Handler
+	record  slog.Record
+}
+
+// flightBuffer is a bounded ring of buffered records, allocated on first use
+type flightBuffer struct {
+	mu       sync.Mutex
+	capacity int
+	entries  []flightEntry
+	head     int
+	size     int
+}
+
+// flightBufferKey is the context key for request flight buffers
+type flightBufferKey struct{}
+
+// backfillKey is the context key marking replayed records
+type backfillKey struct{}
+
+// IsBackfill reports whether ctx is the context of a record replayed by a
+// FlightRecorderHandler. Replayed records are below the level of the sinks,
+// so handlers that check the level in Handle must let them through.
+func IsBackfill(ctx context.Context) bool {
+	backfill, _ := ctx.Value(backfillKey{}).(bool)
+	return backfill
+}
+
+// newFlightBuffer creates a ring holding up to n records
+func newFlightBuffer(n int) *flightBuffer {
+	return &flightBuffer{capacity: n}
+}
+
+// add appends an entry, overwriting the oldest one when full
+func (b *flightBuffer) add(e flightEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.entries == nil {
+		b.entries = make([]flightEntry, b.capacity)
+	}
+	if b.size == len(b.entries) {
+		b.entries[b.head] = e
+		b.head = (b.head + 1) % len(b.entries)
+		return
+	}
+	b.entries[(b.head+b.size)%len(b.entries)] = e
+	b.size++
+}
+
+// drain removes and returns the buffered entries, oldest first
+func (b *flightBuffer) drain() []flightEntry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	out := make([]flightEntry, 0, b.size)
+	for i := range b.size {
+		idx := (b.head + i) % len(b.entries)
+		out = append(out, b.entries[idx])
+		b.entries[idx] = flightEntry{}
+	}
+	b.head, b.size = 0, 0
+
+	return out
+}
+
+// ContextWithFlightRecorder returns a copy of ctx with its own flight recorder
+// buffer, so an error logged with the context only replays the records of the
+// same request. The buffer is allocated when the first record is kept and
+// released with the context.
+func ContextWithFlightRecorder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, flightBufferKey{}, newFlightBuffer(DefaultFlightRecorderPerContextSize))
+}
+
+// HasFlightRecorder reports whether l writes through a FlightRecorderHandler,
+// as the loggers created by Open with EnableFlightRecorder do
+func HasFlightRecorder(l *slog.Logger) bool {
+	_, ok := l.Handler().(*FlightRecorderHandler)
+	return ok
+}
+
+// FlightRecorderHandler is a slog.Handler that keeps the last records below
+// the level of the next handler in memory instead of dropping them, and
+// writes them, marked with a backfill attribute, before the next record at
+// the trigger level.
+type FlightRecorderHandler struct {
+	next     slog.Handler
+	global   *flightBuffer
+	minLevel slog.Level
+	trigger  slog.Level
+}
+
+// NewFlightRecorderHandler creates a new FlightRecorderHandler wrapping next.
+func NewFlightRecorderHandler(next slog.Handler, opts *FlightRecorderOptions) *FlightRecorderHandler {
+	if opts == nil {
+		opts = &FlightRecorderOptions{}
+	}
+
+	size := opts.Size
+	if size <= 0 {
+		size = DefaultFlightRecorderSize
+	}
+
+	h := &FlightRecorderHandler{
+		next:     next,
+		global:   newFlightBuffer(size),
+		minLevel: LevelTrace,
+		trigger:  slog.LevelError,
+	}
+	if opts.MinLevel != nil {
+		h.minLevel = *opts.MinLevel
+	}
+	if opts.TriggerLevel != nil {
+		h.trigger = *opts.TriggerLevel
+	}
+
+	return h
+}
+
+// Enabled implements slog.Handler.
+func (h *FlightRecorderHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.minLevel || h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+//
+//nolint:gocritic // Cannot change signature due to interface contract
+func (h *FlightRecorderHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	buf := h.global
+	if b, ok := ctx.Value(flightBufferKey{}).(*flightBuffer); ok {
+		buf = b
+	}
+
+	// Keep records the next handler would drop
+	if !h.next.Enabled(ctx, r.Level) {
+		if r.Level >= h.minLevel {
+			buf.add(flightEntry{
+				ctx:     context.WithoutCancel(ctx),
+				handler: h.next,
+				record:  r.Clone(),
+			})
+		}
+		return nil
+	}
+
+	var errs []error
+	if r.Level >= h.trigger {
+		for _, e := range buf.drain() {
+			e.record.AddAttrs(slog.Bool(BackfillKey, true))
+			if err := e.handler.Handle(context.WithValue(e.ctx, backfillKey{}, true), e.record); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	errs = append(errs, h.next.Handle(ctx, r))
+	return errors.Join(errs...)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *FlightRecorderHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.next = h.next.WithAttrs(attrs)
+	return &h2
+}
+
+// WithGroup implements slog.Handler.
+func (h *FlightRecorderHandler) WithGroup(name string) slog.Handler {
+	h2 := *h
+	h2.next = h.next.WithGroup(name)
+	return &h2
+}
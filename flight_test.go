@@ -0,0 +1,184 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// leveledMock is a MockLogger that only accepts records at or above level
+type leveledMock struct {
+	*MockLogger
+	level slog.Level
+}
+
+func (m leveledMock) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= m.level
+}
+
+func newTestFlightRecorder(opts *FlightRecorderOptions) (*slog.Logger, *MockLogger) {
+	mock := &MockLogger{}
+	handler := NewFlightRecorderHandler(leveledMock{MockLogger: mock, level: slog.LevelInfo}, opts)
+	return slog.New(handler), mock
+}
+
+func backfilled(entry LogEntry) bool {
+	for _, attr := range entry.Attrs {
+		if attr.Key == BackfillKey {
+			return attr.Value.Bool()
+		}
+	}
+	return false
+}
+
+func TestFlightRecorderHandler(t *testing.T) {
+	log, mock := newTestFlightRecorder(nil)
+	ctx := context.Background()
+
+	log.Log(ctx, LevelTrace, "trace step")
+	log.Debug("debug step", "n", 1)
+	log.Info("info step")
+
+	// Only the info record is written until an error occurs
+	logs := mock.GetLogs()
+	require.Len(t, logs, 1)
+	assert.Equal(t, "info step", logs[0].Message)
+
+	log.Error("failed")
+
+	logs = mock.GetLogs()
+	require.Len(t, logs, 4)
+	assert.Equal(t, "trace step", logs[1].Message)
+	assert.True(t, backfilled(logs[1]))
+	assert.Equal(t, "debug step", logs[2].Message)
+	assert.True(t, backfilled(logs[2]))
+	assert.Equal(t, "failed", logs[3].Message)
+	assert.False(t, backfilled(logs[3]))
+
+	// The buffer is drained by the error
+	log.Error("failed again")
+	assert.Len(t, mock.GetLogs(), 5)
+}
+
+func TestFlightRecorderHandlerContext(t *testing.T) {
+	log, mock := newTestFlightRecorder(nil)
+
+	ctx1 := ContextWithFlightRecorder(context.Background())
+	ctx2 := ContextWithFlightRecorder(context.Background())
+
+	log.DebugContext(ctx1, "request 1")
+	log.DebugContext(ctx2, "request 2")
+	log.Debug("background")
+
+	log.ErrorContext(ctx1, "request 1 failed")
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 2)
+	assert.Equal(t, "request 1", logs[0].Message)
+	assert.True(t, backfilled(logs[0]))
+	assert.Equal(t, "request 1 failed", logs[1].Message)
+
+	log.Error("background failed")
+
+	logs = mock.GetLogs()
+	require.Len(t, logs, 4)
+	assert.Equal(t, "background", logs[2].Message)
+	assert.Equal(t, "background failed", logs[3].Message)
+}
+
+func TestFlightRecorderHandlerOptions(t *testing.T) {
+	minLevel := slog.LevelDebug
+	trigger := slog.LevelWarn
+	log, mock := newTestFlightRecorder(&FlightRecorderOptions{
+		Size:         2,
+		MinLevel:     &minLevel,
+		TriggerLevel: &trigger,
+	})
+
+	log.Log(context.Background(), LevelTrace, "not recorded")
+	for _, msg := range []string{"one", "two", "three"} {
+		log.Debug(msg)
+	}
+	log.Warn("warning")
+
+	// The ring keeps the newest records
+	logs := mock.GetLogs()
+	require.Len(t, logs, 3)
+	assert.Equal(t, "two", logs[0].Message)
+	assert.Equal(t, "three", logs[1].Message)
+	assert.Equal(t, "warning", logs[2].Message)
+}
+
+func TestFlightRecorderHandlerEnabled(t *testing.T) {
+	minLevel := slog.LevelDebug
+	handler := NewFlightRecorderHandler(leveledMock{MockLogger: &MockLogger{}, level: slog.LevelInfo},
+		&FlightRecorderOptions{MinLevel: &minLevel})
+
+	ctx := context.Background()
+	assert.False(t, handler.Enabled(ctx, LevelTrace))
+	assert.True(t, handler.Enabled(ctx, slog.LevelDebug))
+	assert.True(t, handler.Enabled(ctx, slog.LevelInfo))
+}
+
+func TestOpenFlightRecorder(t *testing.T) {
+	log, err := Open(&Config{
+		Level:                "info",
+		Format:               FormatJSON,
+		Sinks:                []string{SinkStderr},
+		EnableMetrics:        true,
+		EnableFlightRecorder: true,
+	})
+	require.NoError(t, err)
+	assert.True(t, HasFlightRecorder(log.Logger))
+	assert.True(t, HasFlightRecorder(log.With("service", "api")))
+
+	// Buffered records are not counted until they are replayed
+	log.Debug("buffered")
+	log.Debug("buffered")
+	m := log.Metrics()
+	assert.Equal(t, uint64(0), m.Value(MetricRecords, "level", "debug", "logger", ""))
+
+	log.Error("failed")
+	require.NoError(t, log.Close(context.Background()))
+	assert.Equal(t, uint64(2), m.Value(MetricRecords, "level", "debug", "logger", ""))
+	assert.Equal(t, uint64(1), m.Value(MetricRecords, "level", "error", "logger", ""))
+
+	plain, err := Open(&Config{Level: "info", Format: FormatJSON})
+	require.NoError(t, err)
+	assert.False(t, HasFlightRecorder(plain.Logger))
+}
+
+func TestOpenFlightRecorderSinks(t *testing.T) {
+	bufs := map[string]*bytes.Buffer{"flight_a": {}, "flight_b": {}}
+	for name, buf := range bufs {
+		RegisterSink(name, func(_ *Config, opts *slog.HandlerOptions) (slog.Handler, error) {
+			return slog.NewJSONHandler(buf, opts), nil
+		})
+	}
+	t.Cleanup(func() {
+		sinksMu.Lock()
+		defer sinksMu.Unlock()
+		delete(sinks, "flight_a")
+		delete(sinks, "flight_b")
+	})
+
+	log, err := Open(&Config{Level: "info", Sinks: []string{"flight_a", "flight_b"}, EnableFlightRecorder: true})
+	require.NoError(t, err)
+
+	log.Debug("buffered")
+	log.Error("failed")
+	require.NoError(t, log.Close(context.Background()))
+
+	// Replayed records reach every sink, although they are below its level
+	for name, buf := range bufs {
+		records := decodeRecords(t, buf)
+		require.Len(t, records, 2, name)
+		assert.Equal(t, "buffered", records[0]["msg"], name)
+		assert.Equal(t, true, records[0][BackfillKey], name)
+		assert.Equal(t, "failed", records[1]["msg"], name)
+	}
+}
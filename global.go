@@ -1,25 +1,121 @@
 package logger
 
 import (
+	"context"
+	"log"
 	"log/slog"
 	"sync"
+	"time"
 )
 
+// replaceCloseTimeout bounds the Close of a default logger replaced by another
+const replaceCloseTimeout = 5 * time.Second
+
 var (
 	// defaultLogger is the default logger instance
 	defaultLogger *slog.Logger
 
-	// mu protects defaultLogger
+	// defaultHandle is the closable logger installed with SetDefaultLogger
+	defaultHandle *Logger
+
+	// defaultRestore undoes the redirections to defaultHandle made by Init, in order
+	defaultRestore []func()
+
+	// mu protects defaultLogger, defaultHandle and defaultRestore
 	mu sync.RWMutex
 )
 
-// SetDefault sets the default logger instance
+// SetDefault sets the default logger instance. A logger previously
+// installed by Init or SetDefaultLogger is not closed, as loggers derived
+// from it may still be in use, and Shutdown still flushes and closes it.
 func SetDefault(logger *slog.Logger) {
 	mu.Lock()
 	defer mu.Unlock()
 	defaultLogger = logger
 }
 
+// SetDefaultLogger sets the default logger instance, which Shutdown flushes
+// and closes. A logger previously installed by Init or SetDefaultLogger is closed.
+func SetDefaultLogger(logger *Logger) {
+	replaceDefault(logger.Logger, logger)
+}
+
+// replaceDefault installs the default logger, then undoes the redirections
+// to the previous handle and closes it
+func replaceDefault(logger *slog.Logger, handle *Logger) {
+	prev, restore := swapDefault(logger, handle)
+	if prev == nil || prev == handle {
+		return
+	}
+
+	runRestore(restore)
+	ctx, cancel := context.WithTimeout(context.Background(), replaceCloseTimeout)
+	defer cancel()
+	_ = prev.Close(ctx)
+}
+
+// swapDefault installs the default logger and returns the previous handle
+// with the functions undoing its redirections
+func swapDefault(logger *slog.Logger, handle *Logger) (*Logger, []func()) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	prev, restore := defaultHandle, defaultRestore
+	defaultLogger = logger
+	defaultHandle = handle
+	if prev != handle {
+		defaultRestore = nil
+	}
+	return prev, restore
+}
+
+// releaseDefault forgets the installed handle, resetting the default logger
+// unless SetDefault replaced it, and returns the handle with the functions
+// undoing its redirections
+func releaseDefault() (*Logger, []func()) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	prev, restore := defaultHandle, defaultRestore
+	if prev != nil {
+		if defaultLogger == prev.Logger {
+			defaultLogger = nil
+		}
+		defaultHandle, defaultRestore = nil, nil
+	}
+	return prev, restore
+}
+
+// addDefaultRestore registers a function undoing a redirection to the default handle
+func addDefaultRestore(fn func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	defaultRestore = append(defaultRestore, fn)
+}
+
+// runRestore undoes redirections, the last one first
+func runRestore(restore []func()) {
+	for i := len(restore) - 1; i >= 0; i-- {
+		restore[i]()
+	}
+}
+
+// setSlogDefault installs l with slog.SetDefault and returns a function
+// restoring the previous slog default and the log package output it replaces
+func setSlogDefault(l *slog.Logger) (restore func()) {
+	prev := slog.Default()
+	std := log.Default()
+	output, flags := std.Writer(), std.Flags()
+
+	slog.SetDefault(l)
+
+	return func() {
+		slog.SetDefault(prev)
+		std.SetOutput(output)
+		std.SetFlags(flags)
+	}
+}
+
 // Default returns the default logger instance
 func Default() *slog.Logger {
 	mu.RLock()
@@ -0,0 +1,537 @@
+// Package grpclog provides gRPC interceptors for request logging.
+//
+// The server interceptors attach a per-call logger to the context, continue
+// the caller's traceparent and x-request-id metadata and log one record per
+// call when it completes. The client interceptors propagate that metadata and
+// log outgoing calls:
+//
+//	server := grpc.NewServer(
+//		grpc.ChainUnaryInterceptor(grpclog.UnaryServerInterceptor(nil)),
+//		grpc.ChainStreamInterceptor(grpclog.StreamServerInterceptor(nil)),
+//	)
+package grpclog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/legrch/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Metadata keys
+const (
+	MetadataRequestID   = "x-request-id"
+	MetadataTraceparent = "traceparent"
+)
+
+// Attribute keys
+const (
+	MethodKey    = "grpc.method"
+	KindKey      = "grpc.kind"
+	CodeKey      = "grpc.code"
+	SentKey      = "grpc.sent"
+	ReceivedKey  = "grpc.received"
+	RequestKey   = "grpc.request"
+	ResponseKey  = "grpc.response"
+	PeerKey      = "peer"
+	DeadlineKey  = "deadline"
+	DurationKey  = "duration"
+	ErrorKey     = "error"
+	RequestIDKey = "request_id"
+
+	// ParentSpanIDKey is the span ID of the caller, from its traceparent
+	ParentSpanIDKey = "parent_span_id"
+)
+
+// Default messages of the completion records
+const (
+	DefaultServerMsg = "grpc request"
+	DefaultClientMsg = "grpc call"
+)
+
+// DefaultMaxPayloadSize is the default cap on logged payloads in bytes
+const DefaultMaxPayloadSize = 1024
+
+// Options configures the interceptors
+type Options struct {
+	// Logger is the base logger, defaults to logger.Default()
+	Logger *slog.Logger
+
+	// Message is the message of the completion record, defaults to
+	// DefaultServerMsg or DefaultClientMsg
+	Message string
+
+	// Level maps a status code to a log level, defaults to CodeToLevel
+	Level func(code codes.Code) slog.Level
+
+	// Skip reports whether calls to a method should not be logged, such as health checks
+	Skip func(fullMethod string) bool
+
+	// LogPayloads logs the first request and response messages of each call
+	LogPayloads bool
+
+	// MaxPayloadSize caps logged payloads in bytes, defaults to DefaultMaxPayloadSize
+	MaxPayloadSize int
+}
+
+// contextKey is the type of the context keys defined by this package
+type contextKey int
+
+const (
+	loggerKey contextKey = iota
+	requestIDKey
+)
+
+// CodeToLevel maps a status code to a log level: client-caused codes are info,
+// transient failures warn and server faults error
+func CodeToLevel(code codes.Code) slog.Level {
+	switch code {
+	case codes.OK, codes.Canceled, codes.InvalidArgument, codes.NotFound,
+		codes.AlreadyExists, codes.Unauthenticated:
+		return slog.LevelInfo
+	case codes.DeadlineExceeded, codes.PermissionDenied, codes.ResourceExhausted,
+		codes.FailedPrecondition, codes.Aborted, codes.OutOfRange, codes.Unavailable:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// WithLogger returns a copy of ctx carrying log
+func WithLogger(ctx context.Context, log *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, log)
+}
+
+// FromContext returns the per-call logger, or logger.Default() if ctx has none
+func FromContext(ctx context.Context) *slog.Logger {
+	if log, ok := ctx.Value(loggerKey).(*slog.Logger); ok {
+		return log
+	}
+	return logger.Default()
+}
+
+// RequestIDFromContext returns the request ID of the call
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// UnaryServerInterceptor returns an interceptor that logs unary calls
+func UnaryServerInterceptor(opts *Options) grpc.UnaryServerInterceptor {
+	o := newOptions(opts, DefaultServerMsg)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, c := o.startServerCall(ctx, info.FullMethod, "unary")
+		c.onRecv(req)
+
+		resp, err := handler(ctx, req)
+		if err == nil {
+			c.onSend(resp)
+		}
+
+		c.finish(err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns an interceptor that logs streaming calls
+func StreamServerInterceptor(opts *Options) grpc.StreamServerInterceptor {
+	o := newOptions(opts, DefaultServerMsg)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, c := o.startServerCall(ss.Context(), info.FullMethod, streamKind(info.IsClientStream, info.IsServerStream))
+
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx, call: c})
+
+		c.finish(err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor returns an interceptor that logs outgoing unary calls
+func UnaryClientInterceptor(opts *Options) grpc.UnaryClientInterceptor {
+	o := newOptions(opts, DefaultClientMsg)
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		ctx, c := o.startClientCall(ctx, method, "unary", cc.Target())
+		c.onSend(req)
+
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		if err == nil {
+			c.onRecv(reply)
+		}
+
+		c.finish(err)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns an interceptor that logs outgoing streaming calls
+func StreamClientInterceptor(opts *Options) grpc.StreamClientInterceptor {
+	o := newOptions(opts, DefaultClientMsg)
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, c := o.startClientCall(ctx, method, streamKind(desc.ClientStreams, desc.ServerStreams), cc.Target())
+
+		cs, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			c.finish(err)
+			return nil, err
+		}
+
+		// A stream abandoned by canceling its context never returns a final
+		// status to RecvMsg. The stream context is done when the stream ends
+		// in any way, so the goroutine does not outlive it.
+		go func() {
+			<-cs.Context().Done()
+			if err := ctx.Err(); err != nil {
+				c.finish(status.FromContextError(err).Err())
+			}
+		}()
+
+		return &clientStream{ClientStream: cs, call: c, serverStreams: desc.ServerStreams}, nil
+	}
+}
+
+// options holds the resolved interceptor options
+type options struct {
+	base           *slog.Logger
+	message        string
+	level          func(codes.Code) slog.Level
+	skip           func(string) bool
+	logPayloads    bool
+	maxPayloadSize int
+}
+
+// newOptions resolves opts and its defaults
+func newOptions(opts *Options, message string) *options {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	o := &options{
+		base:           opts.Logger,
+		message:        opts.Message,
+		level:          opts.Level,
+		skip:           opts.Skip,
+		logPayloads:    opts.LogPayloads,
+		maxPayloadSize: opts.MaxPayloadSize,
+	}
+	if o.message == "" {
+		o.message = message
+	}
+	if o.level == nil {
+		o.level = CodeToLevel
+	}
+	if o.maxPayloadSize <= 0 {
+		o.maxPayloadSize = DefaultMaxPayloadSize
+	}
+
+	return o
+}
+
+// logger returns the base logger
+func (o *options) logger() *slog.Logger {
+	if o.base != nil {
+		return o.base
+	}
+	return logger.Default()
+}
+
+// startServerCall prepares the context and call state of an incoming call
+func (o *options) startServerCall(ctx context.Context, method, kind string) (context.Context, *call) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	requestID := first(md, MetadataRequestID)
+	if !logger.ValidRequestID(requestID) {
+		requestID = logger.NewRequestID()
+	}
+
+	// Continue the caller's trace in a new server span, or start a new trace
+	tc, parentSpanID := logger.ServerTraceContext(first(md, MetadataTraceparent))
+	ctx = logger.ContextWithTraceContext(ctx, tc)
+	if logger.HasFlightRecorder(o.logger()) {
+		ctx = logger.ContextWithFlightRecorder(ctx)
+	}
+
+	var peerAddr string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		peerAddr = p.Addr.String()
+	}
+
+	ctx, c := o.startCall(ctx, true, method, kind, peerAddr, requestID)
+	c.parentSpanID = parentSpanID
+	return ctx, c
+}
+
+// startClientCall prepares the context and call state of an outgoing call
+func (o *options) startClientCall(ctx context.Context, method, kind, target string) (context.Context, *call) {
+	requestID := RequestIDFromContext(ctx)
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if requestID != "" && len(md.Get(MetadataRequestID)) == 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, MetadataRequestID, requestID)
+	}
+	if tc, ok := logger.ExtractTraceContext(ctx); ok && len(md.Get(MetadataTraceparent)) == 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, MetadataTraceparent, tc.Traceparent())
+	}
+
+	return o.startCall(ctx, false, method, kind, target, requestID)
+}
+
+// startCall attaches the per-call logger to ctx and returns the call state
+func (o *options) startCall(ctx context.Context, server bool, method, kind, peerAddr, requestID string) (context.Context, *call) {
+	attrs := []any{slog.String(MethodKey, method)}
+	if requestID != "" {
+		attrs = append(attrs, slog.String(RequestIDKey, requestID))
+		ctx = context.WithValue(ctx, requestIDKey, requestID)
+	}
+
+	c := &call{
+		opts:      o,
+		skipped:   o.skip != nil && o.skip(method),
+		ctx:       ctx,
+		server:    server,
+		method:    method,
+		kind:      kind,
+		peer:      peerAddr,
+		requestID: requestID,
+		start:     time.Now(),
+	}
+
+	return WithLogger(ctx, o.logger().With(attrs...)), c
+}
+
+// call holds the state of a single call until it is logged
+type call struct {
+	opts      *options
+	ctx       context.Context
+	server    bool
+	method    string
+	kind      string
+	peer      string
+	requestID string
+	start     time.Time
+	once      sync.Once
+
+	// parentSpanID is the span ID of the caller of a server call
+	parentSpanID string
+
+	// skipped calls are not logged, so their payloads are not formatted
+	skipped bool
+
+	// mu protects the message counters and payloads, as streams may send
+	// and receive from different goroutines
+	mu       sync.Mutex
+	sent     int64
+	received int64
+	request  string
+	response string
+}
+
+// onSend records a sent message
+func (c *call) onSend(m any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sent++
+	if c.sent == 1 && c.opts.logPayloads && !c.skipped {
+		// Clients send requests, servers send responses
+		if c.server {
+			c.response = c.opts.payload(m)
+		} else {
+			c.request = c.opts.payload(m)
+		}
+	}
+}
+
+// onRecv records a received message
+func (c *call) onRecv(m any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.received++
+	if c.received == 1 && c.opts.logPayloads && !c.skipped {
+		// Servers receive requests, clients receive responses
+		if c.server {
+			c.request = c.opts.payload(m)
+		} else {
+			c.response = c.opts.payload(m)
+		}
+	}
+}
+
+// finish logs the completed call once
+func (c *call) finish(err error) {
+	c.once.Do(func() { c.write(err) })
+}
+
+// write logs the completion record
+func (c *call) write(err error) {
+	if c.skipped {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	code := status.Code(err)
+	attrs := []slog.Attr{
+		slog.String(MethodKey, c.method),
+		slog.String(KindKey, c.kind),
+		slog.String(CodeKey, code.String()),
+		slog.Duration(DurationKey, time.Since(c.start)),
+		slog.Int64(SentKey, c.sent),
+		slog.Int64(ReceivedKey, c.received),
+	}
+
+	if c.requestID != "" {
+		attrs = append(attrs, slog.String(RequestIDKey, c.requestID))
+	}
+	if c.peer != "" {
+		attrs = append(attrs, slog.String(PeerKey, c.peer))
+	}
+	if c.parentSpanID != "" {
+		attrs = append(attrs, slog.String(ParentSpanIDKey, c.parentSpanID))
+	}
+	if deadline, ok := c.ctx.Deadline(); ok {
+		attrs = append(attrs, slog.Time(DeadlineKey, deadline))
+	}
+	if c.request != "" {
+		attrs = append(attrs, slog.String(RequestKey, c.request))
+	}
+	if c.response != "" {
+		attrs = append(attrs, slog.String(ResponseKey, c.response))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String(ErrorKey, status.Convert(err).Message()))
+	}
+
+	c.opts.logger().LogAttrs(c.ctx, c.opts.level(code), c.opts.message, attrs...)
+}
+
+// payload formats a message for logging, capped at the configured size
+func (o *options) payload(msg any) string {
+	var s string
+	if m, ok := msg.(proto.Message); ok {
+		b, err := protojson.Marshal(m)
+		if err != nil {
+			s = fmt.Sprintf("<unmarshalable: %v>", err)
+		} else {
+			s = string(b)
+		}
+	} else {
+		s = fmt.Sprintf("%+v", msg)
+	}
+
+	if len(s) <= o.maxPayloadSize {
+		return s
+	}
+
+	// Cut at a rune boundary so the truncated payload stays valid UTF-8
+	cut := o.maxPayloadSize
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "...(truncated)"
+}
+
+// serverStream counts messages and carries the per-call context
+type serverStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	call *call
+}
+
+// Context implements grpc.ServerStream.
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// SendMsg implements grpc.ServerStream.
+func (s *serverStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.call.onSend(m)
+	}
+	return err
+}
+
+// RecvMsg implements grpc.ServerStream.
+func (s *serverStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.call.onRecv(m)
+	}
+	return err
+}
+
+// clientStream counts messages and logs the call when the stream ends
+type clientStream struct {
+	grpc.ClientStream
+	call          *call
+	serverStreams bool
+}
+
+// SendMsg implements grpc.ClientStream.
+func (s *clientStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.call.onSend(m)
+	} else if !errors.Is(err, io.EOF) {
+		// io.EOF means the stream ended, the status is returned by RecvMsg
+		s.call.finish(err)
+	}
+	return err
+}
+
+// RecvMsg implements grpc.ClientStream.
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == nil:
+		s.call.onRecv(m)
+		// A stream without server streaming ends after its single response
+		if !s.serverStreams {
+			s.call.finish(nil)
+		}
+	case errors.Is(err, io.EOF):
+		s.call.finish(nil)
+	default:
+		s.call.finish(err)
+	}
+	return err
+}
+
+// streamKind describes the streaming mode of a method
+func streamKind(clientStreams, serverStreams bool) string {
+	switch {
+	case clientStreams && serverStreams:
+		return "bidi_stream"
+	case clientStreams:
+		return "client_stream"
+	default:
+		return "server_stream"
+	}
+}
+
+// first returns the first metadata value for key
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
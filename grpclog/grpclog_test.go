@@ -0,0 +1,262 @@
+package grpclog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/legrch/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// contextCheckingServer records the per-call context seen by the handler
+type contextCheckingServer struct {
+	*health.Server
+	requestIDs chan string
+}
+
+func (s *contextCheckingServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	s.requestIDs <- RequestIDFromContext(ctx)
+	return s.Server.Check(ctx, req)
+}
+
+func setup(t *testing.T, serverOpts, clientOpts *Options) (healthpb.HealthClient, *contextCheckingServer) {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor(serverOpts)),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor(serverOpts)),
+	)
+
+	svc := &contextCheckingServer{Server: health.NewServer(), requestIDs: make(chan string, 1)}
+	svc.SetServingStatus("ok", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, svc)
+
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(UnaryClientInterceptor(clientOpts)),
+		grpc.WithChainStreamInterceptor(StreamClientInterceptor(clientOpts)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return healthpb.NewHealthClient(conn), svc
+}
+
+func attrMap(attrs []slog.Attr) map[string]slog.Value {
+	m := make(map[string]slog.Value, len(attrs))
+	for _, attr := range attrs {
+		m[attr.Key] = attr.Value
+	}
+	return m
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	serverLog, clientLog := logger.NewMockLogger(), logger.NewMockLogger()
+	serverMock := serverLog.Handler().(*logger.MockLogger)
+	clientMock := clientLog.Handler().(*logger.MockLogger)
+
+	client, svc := setup(t,
+		&Options{Logger: serverLog, LogPayloads: true},
+		&Options{Logger: clientLog},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, MetadataRequestID, "req-1", MetadataTraceparent, traceparent)
+
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "ok"})
+	require.NoError(t, err)
+	assert.Equal(t, "req-1", <-svc.requestIDs)
+
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "missing"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	<-svc.requestIDs
+
+	logs := serverMock.GetLogs()
+	require.Len(t, logs, 2)
+	assert.Equal(t, DefaultServerMsg, logs[0].Message)
+	assert.Equal(t, slog.LevelInfo, logs[0].Level)
+
+	attrs := attrMap(logs[0].Attrs)
+	assert.Equal(t, healthpb.Health_Check_FullMethodName, attrs[MethodKey].String())
+	assert.Equal(t, "unary", attrs[KindKey].String())
+	assert.Equal(t, "OK", attrs[CodeKey].String())
+	assert.Equal(t, "req-1", attrs[RequestIDKey].String())
+	assert.Equal(t, "00f067aa0ba902b7", attrs[ParentSpanIDKey].String())
+	assert.Equal(t, int64(1), attrs[SentKey].Int64())
+	assert.Equal(t, int64(1), attrs[ReceivedKey].Int64())
+	assert.Equal(t, `{"service":"ok"}`, attrs[RequestKey].String())
+	assert.Equal(t, `{"status":"SERVING"}`, attrs[ResponseKey].String())
+	assert.Contains(t, attrs, PeerKey)
+	assert.Contains(t, attrs, DeadlineKey)
+
+	attrs = attrMap(logs[1].Attrs)
+	assert.Equal(t, "NotFound", attrs[CodeKey].String())
+	assert.Equal(t, int64(0), attrs[SentKey].Int64())
+	assert.Contains(t, attrs, ErrorKey)
+
+	clientLogs := clientMock.GetLogs()
+	require.Len(t, clientLogs, 2)
+	assert.Equal(t, DefaultClientMsg, clientLogs[0].Message)
+	assert.NotContains(t, attrMap(clientLogs[0].Attrs), RequestKey)
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	serverLog, clientLog := logger.NewMockLogger(), logger.NewMockLogger()
+	serverMock := serverLog.Handler().(*logger.MockLogger)
+	clientMock := clientLog.Handler().(*logger.MockLogger)
+
+	client, _ := setup(t, &Options{Logger: serverLog}, &Options{Logger: clientLog})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "ok"})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+
+	cancel()
+	_, err = stream.Recv()
+	require.Equal(t, codes.Canceled, status.Code(err))
+
+	clientLogs := clientMock.GetLogs()
+	require.Len(t, clientLogs, 1)
+	attrs := attrMap(clientLogs[0].Attrs)
+	assert.Equal(t, "server_stream", attrs[KindKey].String())
+	assert.Equal(t, "Canceled", attrs[CodeKey].String())
+	assert.Equal(t, int64(1), attrs[SentKey].Int64())
+	assert.Equal(t, int64(1), attrs[ReceivedKey].Int64())
+
+	require.Eventually(t, func() bool { return len(serverMock.GetLogs()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	attrs = attrMap(serverMock.GetLogs()[0].Attrs)
+	assert.Equal(t, healthpb.Health_Watch_FullMethodName, attrs[MethodKey].String())
+	assert.Equal(t, int64(1), attrs[ReceivedKey].Int64())
+	assert.Equal(t, int64(1), attrs[SentKey].Int64())
+}
+
+func TestAbandonedClientStream(t *testing.T) {
+	clientLog := logger.NewMockLogger()
+	client, _ := setup(t, &Options{Logger: logger.NewMockLogger()}, &Options{Logger: clientLog})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "ok"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	// The stream is canceled without reading its final status
+	cancel()
+
+	clientMock := logger.GetMockLogger(clientLog)
+	require.Eventually(t, func() bool { return len(clientMock.GetLogs()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "Canceled", attrMap(clientMock.GetLogs()[0].Attrs)[CodeKey].String())
+}
+
+func TestRequestIDLength(t *testing.T) {
+	serverLog := logger.NewMockLogger()
+	client, svc := setup(t, &Options{Logger: serverLog}, &Options{Logger: logger.NewMockLogger()})
+
+	long := strings.Repeat("x", logger.MaxRequestIDLength+1)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), MetadataRequestID, long)
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "ok"})
+	require.NoError(t, err)
+
+	id := <-svc.requestIDs
+	assert.NotEqual(t, long, id)
+	assert.Len(t, id, 32)
+}
+
+func TestNewTrace(t *testing.T) {
+	interceptor := UnaryServerInterceptor(&Options{Logger: logger.NewMockLogger()})
+
+	var tc logger.TraceContext
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/svc/Method"},
+		func(ctx context.Context, _ any) (any, error) {
+			tc, _ = logger.ExtractTraceContext(ctx)
+			return nil, nil
+		})
+	require.NoError(t, err)
+
+	// A call without traceparent starts a new trace, as httplog does
+	assert.True(t, tc.IsValid())
+	assert.False(t, tc.IsSampled())
+}
+
+// countingPayload counts how often it is formatted
+type countingPayload struct {
+	formats *int
+}
+
+func (p countingPayload) Format(f fmt.State, _ rune) {
+	*p.formats++
+	_, _ = f.Write([]byte("payload"))
+}
+
+func TestSkipPayload(t *testing.T) {
+	formats := 0
+	interceptor := UnaryServerInterceptor(&Options{
+		Logger:      logger.NewMockLogger(),
+		LogPayloads: true,
+		Skip:        func(string) bool { return true },
+	})
+
+	req := countingPayload{formats: &formats}
+	_, err := interceptor(context.Background(), req, &grpc.UnaryServerInfo{FullMethod: "/svc/Method"},
+		func(context.Context, any) (any, error) { return req, nil })
+	require.NoError(t, err)
+	assert.Zero(t, formats)
+}
+
+func TestSkip(t *testing.T) {
+	serverLog := logger.NewMockLogger()
+	serverMock := serverLog.Handler().(*logger.MockLogger)
+
+	client, svc := setup(t, &Options{
+		Logger: serverLog,
+		Skip:   func(method string) bool { return method == healthpb.Health_Check_FullMethodName },
+	}, &Options{Logger: logger.NewMockLogger()})
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "ok"})
+	require.NoError(t, err)
+	<-svc.requestIDs
+
+	assert.Empty(t, serverMock.GetLogs())
+}
+
+func TestCodeToLevel(t *testing.T) {
+	assert.Equal(t, slog.LevelInfo, CodeToLevel(codes.OK))
+	assert.Equal(t, slog.LevelInfo, CodeToLevel(codes.NotFound))
+	assert.Equal(t, slog.LevelWarn, CodeToLevel(codes.Unavailable))
+	assert.Equal(t, slog.LevelError, CodeToLevel(codes.Internal))
+	assert.Equal(t, slog.LevelError, CodeToLevel(codes.Unknown))
+}
+
+func TestPayloadTruncation(t *testing.T) {
+	o := newOptions(&Options{MaxPayloadSize: 5}, DefaultServerMsg)
+	assert.Equal(t, "héll...(truncated)", o.payload("héllo world"))
+	assert.Equal(t, "short", o.payload("short"))
+}
@@ -0,0 +1,299 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// ErrDropRecord is returned by a Before hook to veto the record
+var ErrDropRecord = errors.New("logger: record dropped by hook")
+
+// Hook reacts to records passing through a HookHandler. Hooks must not log
+// through the logger they are registered with, or they will be called again.
+type Hook struct {
+	// Name identifies the hook in error reports
+	Name string
+
+	// MinLevel is the lowest level the hook runs for, if set
+	MinLevel *slog.Level
+
+	// Levels are the only levels the hook runs for, if set
+	Levels []slog.Level
+
+	// Before runs synchronously before the record is written and may modify
+	// it. Returning ErrDropRecord vetoes the record; other errors are
+	// reported and the record is written anyway.
+	Before func(ctx context.Context, r *slog.Record) error
+
+	// After runs asynchronously with a copy of the record once it has been
+	// passed to the next handler, with the error that handler returned.
+	// Records are dropped for After hooks while HookOptions.QueueSize records
+	// are pending.
+	After func(ctx context.Context, r slog.Record, err error)
+}
+
+// matches reports whether the hook runs for level
+func (h *Hook) matches(level slog.Level) bool {
+	if h.MinLevel != nil && level < *h.MinLevel {
+		return false
+	}
+	return len(h.Levels) == 0 || slices.Contains(h.Levels, level)
+}
+
+var (
+	// hooks holds the globally registered hooks
+	hooks []Hook
+
+	// hooksMu protects hooks
+	hooksMu sync.RWMutex
+)
+
+// RegisterHook registers a hook that runs for every record passing through a HookHandler
+func RegisterHook(hook Hook) {
+	if hook.Before == nil && hook.After == nil {
+		return
+	}
+
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	hooks = append(hooks, hook)
+}
+
+// registeredHooks returns a snapshot of the globally registered hooks
+func registeredHooks() []Hook {
+	hooksMu.RLock()
+	defer hooksMu.RUnlock()
+	return hooks[:len(hooks):len(hooks)]
+}
+
+// DefaultHookQueueSize is the default number of records pending for After hooks
+const DefaultHookQueueSize = 1024
+
+// HookOptions configures a HookHandler
+type HookOptions struct {
+	// Hooks run before the globally registered ones
+	Hooks []Hook
+
+	// QueueSize is the maximum number of records pending for After hooks,
+	// defaults to DefaultHookQueueSize
+	QueueSize int
+
+	// OnError is called with errors returned by Before hooks and with hook
+	// panics, defaults to printing them to stderr, or to the original stderr
+	// while it is redirected by CaptureStderr
+	OnError func(hook string, err error)
+}
+
+// hookJob is a record pending for its After hooks
+type hookJob struct {
+	ctx   context.Context
+	hooks []*Hook
+	r     slog.Record
+	err   error
+}
+
+// hookState holds the pending After hooks shared by derived handlers
+type hookState struct {
+	opts    HookOptions
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queue   []hookJob
+	pending int
+	running bool
+	dropped uint64
+}
+
+// HookHandler is a slog.Handler that runs hooks before and after passing
+// records to the next handler. Hook panics are recovered and reported, so a
+// faulty hook cannot break the logging call.
+type HookHandler struct {
+	next  slog.Handler
+	state *hookState
+}
+
+// NewHookHandler creates a new HookHandler wrapping next.
+func NewHookHandler(next slog.Handler, opts *HookOptions) *HookHandler {
+	if opts == nil {
+		opts = &HookOptions{}
+	}
+
+	state := &hookState{opts: *opts}
+	state.cond = sync.NewCond(&state.mu)
+	if state.opts.QueueSize <= 0 {
+		state.opts.QueueSize = DefaultHookQueueSize
+	}
+	if state.opts.OnError == nil {
+		state.opts.OnError = func(hook string, err error) {
+			fmt.Fprintf(errorOutput(), "logger: hook %q: %v\n", hook, err)
+		}
+	}
+
+	return &HookHandler{next: next, state: state}
+}
+
+// Enabled implements slog.Handler.
+func (h *HookHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+//
+//nolint:gocritic // Cannot change signature due to interface contract
+func (h *HookHandler) Handle(ctx context.Context, r slog.Record) error {
+	registered := registeredHooks()
+	if len(h.state.opts.Hooks) == 0 && len(registered) == 0 {
+		return h.next.Handle(ctx, r)
+	}
+
+	var after []*Hook
+	cloned := false
+	for _, list := range [][]Hook{h.state.opts.Hooks, registered} {
+		for i := range list {
+			hook := &list[i]
+			if !hook.matches(r.Level) {
+				continue
+			}
+			if hook.After != nil {
+				after = append(after, hook)
+			}
+			if hook.Before == nil {
+				continue
+			}
+
+			// The record may share its attributes with the caller, so it is
+			// copied before the first hook can modify it
+			if !cloned {
+				r = r.Clone()
+				cloned = true
+			}
+			if err := h.state.before(ctx, hook, &r); errors.Is(err, ErrDropRecord) {
+				return nil
+			}
+		}
+	}
+
+	err := h.next.Handle(ctx, r)
+
+	if len(after) > 0 {
+		h.state.push(hookJob{ctx: context.WithoutCancel(ctx), hooks: after, r: r.Clone(), err: err})
+	}
+
+	return err
+}
+
+// WithAttrs implements slog.Handler.
+func (h *HookHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &HookHandler{next: h.next.WithAttrs(attrs), state: h.state}
+}
+
+// WithGroup implements slog.Handler.
+func (h *HookHandler) WithGroup(name string) slog.Handler {
+	return &HookHandler{next: h.next.WithGroup(name), state: h.state}
+}
+
+// Dropped returns the number of records whose After hooks were skipped
+// because the queue was full
+func (h *HookHandler) Dropped() uint64 {
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+	return h.state.dropped
+}
+
+// Flush waits until no After hooks are running
+func (h *HookHandler) Flush(ctx context.Context) error {
+	s := h.state
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stop := context.AfterFunc(ctx, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.cond.Broadcast()
+	})
+	defer stop()
+
+	for s.pending > 0 {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("flush hooks: %w", err)
+		}
+		s.cond.Wait()
+	}
+
+	return nil
+}
+
+// before runs a Before hook, reporting its errors and panics
+func (s *hookState) before(ctx context.Context, hook *Hook, r *slog.Record) (err error) {
+	defer s.recover(hook)
+
+	err = hook.Before(ctx, r)
+	if err != nil && !errors.Is(err, ErrDropRecord) {
+		s.opts.OnError(hook.Name, err)
+	}
+	return err
+}
+
+// push queues a record for its After hooks, dropping it if the queue is
+// full, and starts the worker goroutine if it is not running
+func (s *hookState) push(job hookJob) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pending >= s.opts.QueueSize {
+		s.dropped++
+		return
+	}
+
+	s.queue = append(s.queue, job)
+	s.pending++
+	if !s.running {
+		s.running = true
+		go s.work()
+	}
+}
+
+// work runs the queued After hooks until the queue is empty, so that no
+// goroutine is left behind by handlers that are never closed
+func (s *hookState) work() {
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			s.running = false
+			s.mu.Unlock()
+			return
+		}
+		job := s.queue[0]
+		s.queue[0] = hookJob{}
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+
+		s.after(job)
+
+		s.mu.Lock()
+		s.pending--
+		s.cond.Broadcast()
+		s.mu.Unlock()
+	}
+}
+
+// after runs the After hooks of a record, isolating them from each other
+func (s *hookState) after(job hookJob) {
+	for _, hook := range job.hooks {
+		func() {
+			defer s.recover(hook)
+			hook.After(job.ctx, job.r, job.err)
+		}()
+	}
+}
+
+// recover reports a panic of hook
+func (s *hookState) recover(hook *Hook) {
+	if v := recover(); v != nil {
+		s.opts.OnError(hook.Name, fmt.Errorf("panic: %v", v))
+	}
+}
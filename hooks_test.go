@@ -0,0 +1,221 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hookErrors collects the errors reported by a HookHandler
+type hookErrors struct {
+	mu   sync.Mutex
+	errs map[string][]error
+}
+
+func (e *hookErrors) report(hook string, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.errs == nil {
+		e.errs = make(map[string][]error)
+	}
+	e.errs[hook] = append(e.errs[hook], err)
+}
+
+func (e *hookErrors) get(hook string) []error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.errs[hook]
+}
+
+func TestHookHandlerBefore(t *testing.T) {
+	mock := &MockLogger{}
+	errs := &hookErrors{}
+	log := slog.New(NewHookHandler(mock, &HookOptions{
+		OnError: errs.report,
+		Hooks: []Hook{
+			{
+				Name: "enrich",
+				Before: func(_ context.Context, r *slog.Record) error {
+					r.AddAttrs(slog.Bool("flag", true))
+					return nil
+				},
+			},
+			{
+				Name: "veto",
+				Before: func(_ context.Context, r *slog.Record) error {
+					if r.Message == "secret" {
+						return ErrDropRecord
+					}
+					return nil
+				},
+			},
+			{
+				Name: "failing",
+				Before: func(context.Context, *slog.Record) error {
+					return errors.New("boom")
+				},
+			},
+		},
+	}))
+
+	log.Info("hello")
+	log.Info("secret")
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 1)
+	assert.Equal(t, "hello", logs[0].Message)
+	assert.Equal(t, []slog.Attr{slog.Bool("flag", true)}, logs[0].Attrs)
+
+	// The failing hook reports its error without vetoing the record
+	require.Len(t, errs.get("failing"), 1)
+	assert.EqualError(t, errs.get("failing")[0], "boom")
+}
+
+func TestHookHandlerAfter(t *testing.T) {
+	var mu sync.Mutex
+	var seen []string
+
+	handler := NewHookHandler(&MockLogger{}, &HookOptions{
+		Hooks: []Hook{{
+			Name: "page",
+			After: func(_ context.Context, r slog.Record, err error) {
+				assert.NoError(t, err)
+				mu.Lock()
+				defer mu.Unlock()
+				seen = append(seen, r.Message)
+			},
+		}},
+	})
+	log := slog.New(handler)
+
+	log.Info("one")
+	log.Error("two")
+	require.NoError(t, handler.Flush(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.ElementsMatch(t, []string{"one", "two"}, seen)
+}
+
+func TestHookHandlerAfterQueue(t *testing.T) {
+	gate := make(chan struct{})
+	var mu sync.Mutex
+	var seen []string
+
+	handler := NewHookHandler(&MockLogger{}, &HookOptions{
+		QueueSize: 2,
+		Hooks: []Hook{{
+			Name: "slow",
+			After: func(_ context.Context, r slog.Record, _ error) {
+				<-gate
+				mu.Lock()
+				defer mu.Unlock()
+				seen = append(seen, r.Message)
+			},
+		}},
+	})
+	log := slog.New(handler)
+
+	log.Info("one")
+	log.Info("two")
+	log.Info("dropped")
+	assert.Equal(t, uint64(1), handler.Dropped())
+
+	close(gate)
+	require.NoError(t, handler.Flush(context.Background()))
+
+	// The worker has exited and is started again for the next record
+	log.Info("three")
+	require.NoError(t, handler.Flush(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"one", "two", "three"}, seen)
+}
+
+func TestHookHandlerLevels(t *testing.T) {
+	var counts sync.Map
+	count := func(name string) func(context.Context, *slog.Record) error {
+		return func(context.Context, *slog.Record) error {
+			n, _ := counts.LoadOrStore(name, new(int))
+			*n.(*int)++
+			return nil
+		}
+	}
+
+	minLevel := slog.LevelWarn
+	log := slog.New(NewHookHandler(&MockLogger{}, &HookOptions{
+		Hooks: []Hook{
+			{Name: "warn+", MinLevel: &minLevel, Before: count("warn+")},
+			{Name: "debug", Levels: []slog.Level{slog.LevelDebug}, Before: count("debug")},
+			{Name: "all", Before: count("all")},
+		},
+	}))
+
+	log.Debug("d")
+	log.Info("i")
+	log.Warn("w")
+	log.Error("e")
+
+	get := func(name string) int {
+		n, ok := counts.Load(name)
+		if !ok {
+			return 0
+		}
+		return *n.(*int)
+	}
+	assert.Equal(t, 2, get("warn+"))
+	assert.Equal(t, 1, get("debug"))
+	assert.Equal(t, 4, get("all"))
+}
+
+func TestHookHandlerPanics(t *testing.T) {
+	mock := &MockLogger{}
+	errs := &hookErrors{}
+	handler := NewHookHandler(mock, &HookOptions{
+		OnError: errs.report,
+		Hooks: []Hook{
+			{Name: "before", Before: func(context.Context, *slog.Record) error { panic("before failed") }},
+			{Name: "after", After: func(context.Context, slog.Record, error) { panic("after failed") }},
+		},
+	})
+
+	require.NotPanics(t, func() { slog.New(handler).Info("still written") })
+	require.NoError(t, handler.Flush(context.Background()))
+
+	assert.Len(t, mock.GetLogs(), 1)
+	require.Len(t, errs.get("before"), 1)
+	assert.EqualError(t, errs.get("before")[0], "panic: before failed")
+	require.Len(t, errs.get("after"), 1)
+	assert.EqualError(t, errs.get("after")[0], "panic: after failed")
+}
+
+func TestRegisterHook(t *testing.T) {
+	t.Cleanup(func() {
+		hooksMu.Lock()
+		defer hooksMu.Unlock()
+		hooks = nil
+	})
+
+	RegisterHook(Hook{Name: "empty"})
+	RegisterHook(Hook{
+		Name: "global",
+		Before: func(_ context.Context, r *slog.Record) error {
+			r.AddAttrs(slog.String("hook", "global"))
+			return nil
+		},
+	})
+	assert.Len(t, registeredHooks(), 1)
+
+	mock := &MockLogger{}
+	slog.New(NewHookHandler(mock, nil)).Info("hello", "a", 1)
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 1)
+	assert.Equal(t, []slog.Attr{slog.Int("a", 1), slog.String("hook", "global")}, logs[0].Attrs)
+}
@@ -0,0 +1,287 @@
+// Package httplog provides net/http middleware for request logging.
+//
+// The middleware injects a request-scoped logger into the request context,
+// propagates the W3C traceparent and X-Request-ID headers and logs one record
+// per request when it completes:
+//
+//	mux := http.NewServeMux()
+//	handler := httplog.Middleware(&httplog.Options{SkipPaths: []string{"/healthz"}})(mux)
+package httplog
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/legrch/logger"
+)
+
+// Header names
+const (
+	HeaderRequestID   = "X-Request-ID"
+	HeaderTraceparent = "traceparent"
+)
+
+// Attribute keys
+const (
+	RequestIDKey = "request_id"
+	MethodKey    = "method"
+	PathKey      = "path"
+	RouteKey     = "route"
+	StatusKey    = "status"
+	BytesKey     = "bytes"
+	DurationKey  = "duration"
+	RemoteIPKey  = "remote_ip"
+	UserAgentKey = "user_agent"
+
+	// ParentSpanIDKey is the span ID of the caller, from its traceparent
+	ParentSpanIDKey = "parent_span_id"
+)
+
+// DefaultMsg is the default message of the completion record
+const DefaultMsg = "http request"
+
+// Options configures the request logging middleware
+type Options struct {
+	// Logger is the base logger, defaults to logger.Default()
+	Logger *slog.Logger
+
+	// Message is the message of the completion record, defaults to DefaultMsg
+	Message string
+
+	// SkipPaths are request paths that are not logged, such as health checks
+	SkipPaths []string
+
+	// Skip reports whether a request should not be logged
+	Skip func(r *http.Request) bool
+
+	// TrustProxyHeaders takes the remote IP from X-Forwarded-For or X-Real-IP
+	TrustProxyHeaders bool
+
+	// GenerateRequestID creates IDs for requests without X-Request-ID
+	GenerateRequestID func() string
+
+	// EchoTraceparent sets the traceparent of the server span on responses.
+	// W3C trace context does not define a response header, so it is off by default.
+	EchoTraceparent bool
+}
+
+// contextKey is the type of the context keys defined by this package
+type contextKey int
+
+const (
+	loggerKey contextKey = iota
+	requestIDKey
+)
+
+// Middleware returns middleware that logs each request handled by the next handler
+func Middleware(opts *Options) func(http.Handler) http.Handler {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	skip := make(map[string]struct{}, len(opts.SkipPaths))
+	for _, path := range opts.SkipPaths {
+		skip[path] = struct{}{}
+	}
+
+	message := opts.Message
+	if message == "" {
+		message = DefaultMsg
+	}
+
+	generateID := opts.GenerateRequestID
+	if generateID == nil {
+		generateID = logger.NewRequestID
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			// Take the request ID from the caller or generate one
+			requestID := r.Header.Get(HeaderRequestID)
+			if !logger.ValidRequestID(requestID) {
+				requestID = generateID()
+			}
+			w.Header().Set(HeaderRequestID, requestID)
+
+			// Continue the caller's trace in a new server span, or start a new trace
+			tc, parentSpanID := logger.ServerTraceContext(r.Header.Get(HeaderTraceparent))
+			if opts.EchoTraceparent {
+				w.Header().Set(HeaderTraceparent, tc.Traceparent())
+			}
+
+			base := opts.Logger
+			if base == nil {
+				base = logger.Default()
+			}
+			log := base.With(RequestIDKey, requestID)
+
+			ctx := logger.ContextWithTraceContext(r.Context(), tc)
+			if logger.HasFlightRecorder(base) {
+				ctx = logger.ContextWithFlightRecorder(ctx)
+			}
+			ctx = context.WithValue(ctx, requestIDKey, requestID)
+			ctx = WithLogger(ctx, log)
+			r = r.WithContext(ctx)
+
+			rw := &responseWriter{ResponseWriter: w}
+			next.ServeHTTP(rw, r)
+
+			if _, ok := skip[r.URL.Path]; ok || (opts.Skip != nil && opts.Skip(r)) {
+				return
+			}
+
+			status := rw.Status()
+			attrs := []slog.Attr{
+				slog.String(RequestIDKey, requestID),
+				slog.String(MethodKey, r.Method),
+				slog.String(PathKey, r.URL.Path),
+				slog.String(RouteKey, r.Pattern),
+				slog.Int(StatusKey, status),
+				slog.Int64(BytesKey, rw.bytes),
+				slog.Duration(DurationKey, time.Since(start)),
+				slog.String(RemoteIPKey, remoteIP(r, opts.TrustProxyHeaders)),
+				slog.String(UserAgentKey, r.UserAgent()),
+			}
+			if parentSpanID != "" {
+				attrs = append(attrs, slog.String(ParentSpanIDKey, parentSpanID))
+			}
+			base.LogAttrs(ctx, Level(status), message, attrs...)
+		})
+	}
+}
+
+// Level returns the log level for a response status: error for 5xx,
+// warn for 4xx and info otherwise
+func Level(status int) slog.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return slog.LevelError
+	case status >= http.StatusBadRequest:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// WithLogger returns a copy of ctx carrying log
+func WithLogger(ctx context.Context, log *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, log)
+}
+
+// FromContext returns the request-scoped logger, or logger.Default() if ctx has none
+func FromContext(ctx context.Context) *slog.Logger {
+	if log, ok := ctx.Value(loggerKey).(*slog.Logger); ok {
+		return log
+	}
+	return logger.Default()
+}
+
+// RequestIDFromContext returns the request ID set by the middleware
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// Transport is an http.RoundTripper that propagates the traceparent and
+// X-Request-ID of the request context to outgoing requests
+type Transport struct {
+	// Base is the underlying round tripper, defaults to http.DefaultTransport
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ctx := r.Context()
+	tc, hasTrace := logger.ExtractTraceContext(ctx)
+	requestID := RequestIDFromContext(ctx)
+	if !hasTrace && requestID == "" {
+		return base.RoundTrip(r)
+	}
+
+	// RoundTrippers must not modify the caller's request
+	r = r.Clone(ctx)
+	if hasTrace && r.Header.Get(HeaderTraceparent) == "" {
+		r.Header.Set(HeaderTraceparent, tc.Traceparent())
+	}
+	if requestID != "" && r.Header.Get(HeaderRequestID) == "" {
+		r.Header.Set(HeaderRequestID, requestID)
+	}
+
+	return base.RoundTrip(r)
+}
+
+// remoteIP returns the client IP of the request
+func remoteIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			first, _, _ := strings.Cut(fwd, ",")
+			return strings.TrimSpace(first)
+		}
+		if ip := r.Header.Get("X-Real-IP"); ip != "" {
+			return strings.TrimSpace(ip)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// responseWriter records the status code and number of bytes written
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter.
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher.
+func (w *responseWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying writer for http.ResponseController
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Status returns the response status, defaulting to 200
+func (w *responseWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
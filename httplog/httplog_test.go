@@ -0,0 +1,164 @@
+package httplog
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/legrch/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func attrMap(attrs []slog.Attr) map[string]slog.Value {
+	m := make(map[string]slog.Value, len(attrs))
+	for _, attr := range attrs {
+		m[attr.Key] = attr.Value
+	}
+	return m
+}
+
+func TestMiddleware(t *testing.T) {
+	log := logger.NewMockLogger()
+	mock := log.Handler().(*logger.MockLogger)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "req-1", RequestIDFromContext(r.Context()))
+		assert.NotNil(t, FromContext(r.Context()))
+
+		tc, ok := logger.TraceContextFromContext(r.Context())
+		assert.True(t, ok)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceIDString())
+		assert.NotEqual(t, "00f067aa0ba902b7", tc.SpanIDString())
+		assert.True(t, tc.IsSampled())
+
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("not found"))
+	})
+
+	handler := Middleware(&Options{Logger: log})(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set(HeaderRequestID, "req-1")
+	req.Header.Set(HeaderTraceparent, traceparent)
+	req.Header.Set("User-Agent", "test-agent")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, "req-1", rec.Header().Get(HeaderRequestID))
+	assert.Empty(t, rec.Header().Get(HeaderTraceparent))
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 1)
+	assert.Equal(t, slog.LevelWarn, logs[0].Level)
+	assert.Equal(t, DefaultMsg, logs[0].Message)
+
+	attrs := attrMap(logs[0].Attrs)
+	assert.Equal(t, "req-1", attrs[RequestIDKey].String())
+	assert.Equal(t, http.MethodGet, attrs[MethodKey].String())
+	assert.Equal(t, "/users/42", attrs[PathKey].String())
+	assert.Equal(t, "GET /users/{id}", attrs[RouteKey].String())
+	assert.Equal(t, int64(http.StatusNotFound), attrs[StatusKey].Int64())
+	assert.Equal(t, int64(len("not found")), attrs[BytesKey].Int64())
+	assert.Equal(t, "192.0.2.1", attrs[RemoteIPKey].String())
+	assert.Equal(t, "test-agent", attrs[UserAgentKey].String())
+	assert.Equal(t, "00f067aa0ba902b7", attrs[ParentSpanIDKey].String())
+	assert.Contains(t, attrs, DurationKey)
+}
+
+func TestMiddlewareGeneratesIDs(t *testing.T) {
+	log := logger.NewMockLogger()
+
+	handler := Middleware(&Options{
+		Logger:            log,
+		GenerateRequestID: func() string { return "generated" },
+		EchoTraceparent:   true,
+	})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, "generated", rec.Header().Get(HeaderRequestID))
+	tc, err := logger.ParseTraceparent(rec.Header().Get(HeaderTraceparent))
+	require.NoError(t, err)
+	assert.True(t, tc.IsValid())
+	assert.NotContains(t, attrMap(logger.GetMockLogger(log).GetLogs()[0].Attrs), ParentSpanIDKey)
+
+	// The echoed traceparent is the server span, in the caller's trace
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderTraceparent, traceparent)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	tc, err = logger.ParseTraceparent(rec.Header().Get(HeaderTraceparent))
+	require.NoError(t, err)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceIDString())
+	assert.NotEqual(t, "00f067aa0ba902b7", tc.SpanIDString())
+}
+
+func TestMiddlewareSkip(t *testing.T) {
+	log := logger.NewMockLogger()
+	mock := log.Handler().(*logger.MockLogger)
+
+	handler := Middleware(&Options{
+		Logger:    log,
+		SkipPaths: []string{"/healthz"},
+		Skip:      func(r *http.Request) bool { return r.Method == http.MethodOptions },
+	})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodOptions, "/api", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api", nil))
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 1)
+	assert.Equal(t, slog.LevelInfo, logs[0].Level)
+}
+
+func TestRemoteIP(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+
+	assert.Equal(t, "192.0.2.1", remoteIP(req, false))
+	assert.Equal(t, "203.0.113.7", remoteIP(req, true))
+}
+
+func TestLevel(t *testing.T) {
+	assert.Equal(t, slog.LevelInfo, Level(http.StatusOK))
+	assert.Equal(t, slog.LevelInfo, Level(http.StatusFound))
+	assert.Equal(t, slog.LevelWarn, Level(http.StatusBadRequest))
+	assert.Equal(t, slog.LevelError, Level(http.StatusServiceUnavailable))
+}
+
+func TestTransport(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	handler := Middleware(&Options{Logger: logger.NewMockLogger()})(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		resp, err := (&http.Client{Transport: &Transport{}}).Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderRequestID, "req-2")
+	req.Header.Set(HeaderTraceparent, traceparent)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "req-2", got.Get(HeaderRequestID))
+	tc, err := logger.ParseTraceparent(got.Get(HeaderTraceparent))
+	require.NoError(t, err)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceIDString())
+	assert.NotEqual(t, "00f067aa0ba902b7", tc.SpanIDString())
+}
@@ -0,0 +1,58 @@
+package httplog
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/legrch/logger"
+)
+
+// Recover returns middleware that logs panics of the next handler with
+// logger.HandlePanic. Use it inside Middleware so the record carries the
+// request logger and the request is logged with its 500 status.
+//
+// Unless another action is configured, the panic is logged at
+// logger.LevelPanic and answered with 500 Internal Server Error if no
+// response was written yet, as with logger.RecoverReturnError.
+// logger.RecoverRepanic panics again. The logger defaults to the request
+// logger from FromContext.
+// http.ErrAbortHandler panics are passed on without logging.
+func Recover(opts *logger.RecoverOptions) func(http.Handler) http.Handler {
+	var o logger.RecoverOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Action == logger.RecoverDefault {
+		o.Action = logger.RecoverReturnError
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := &responseWriter{ResponseWriter: w}
+
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if err, ok := v.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(v)
+				}
+
+				ro := o
+				if ro.Logger == nil {
+					ro.Logger = FromContext(r.Context())
+				}
+				var err error
+				ro.Err = &err
+				logger.HandlePanic(r.Context(), v, &ro)
+
+				if rw.status == 0 {
+					http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+			}()
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package httplog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/legrch/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecover(t *testing.T) {
+	log := logger.NewMockLogger()
+	mock := log.Handler().(*logger.MockLogger)
+
+	handler := Middleware(&Options{Logger: log})(Recover(&logger.RecoverOptions{
+		Logger: log,
+		Action: logger.RecoverReturnError,
+	})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("handler failed")
+	})))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 2)
+	assert.Equal(t, logger.LevelPanic, logs[0].Level)
+	assert.Equal(t, "handler failed", attrMap(logs[0].Attrs)[logger.PanicKey].String())
+	assert.Equal(t, int64(http.StatusInternalServerError), attrMap(logs[1].Attrs)[StatusKey].Int64())
+}
+
+func TestRecoverDefaultAction(t *testing.T) {
+	log := logger.NewMockLogger()
+	handler := Recover(&logger.RecoverOptions{Logger: log})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("unset action")
+	}))
+
+	rec := httptest.NewRecorder()
+	assert.NotPanics(t, func() {
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+	})
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Len(t, log.Handler().(*logger.MockLogger).GetLogs(), 1)
+}
+
+func TestRecoverAbortHandler(t *testing.T) {
+	handler := Recover(nil)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+	})
+}
+
+func TestRecoverRepanic(t *testing.T) {
+	log := logger.NewMockLogger()
+	handler := Recover(&logger.RecoverOptions{Logger: log, Action: logger.RecoverRepanic})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("again")
+	}))
+
+	assert.PanicsWithValue(t, "again", func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+	})
+	assert.Len(t, log.Handler().(*logger.MockLogger).GetLogs(), 1)
+}
@@ -2,6 +2,7 @@ package logger
 
 import (
 	"fmt"
+	"log/slog"
 )
 
 // Format constants
@@ -10,16 +11,41 @@ const (
 	FormatConsole = "console"
 )
 
-// Init initializes the logger with the given configuration and sets it as the default logger
+// Init initializes the logger with the given configuration and sets it as the default logger.
+// With Config.SetSlogDefault and Config.RedirectStdLog it also becomes the
+// slog default and receives the output of the log package, until Shutdown.
+// It also sets the process-wide SetErrorStacks from Config.EnableStacktrace,
+// which applies to Err with every logger and is not undone by Shutdown.
+// Call Shutdown before exiting to flush buffered records.
 func Init(cfg *Config) error {
+	stdLogLevel := slog.LevelInfo
+	if cfg.RedirectStdLog && cfg.StdLogLevel != "" {
+		level, err := parseLogLevel(cfg.StdLogLevel)
+		if err != nil {
+			return fmt.Errorf("invalid std log level: %w", err)
+		}
+		stdLogLevel = level
+	}
+
 	// Create a new logger
-	log, err := New(cfg)
+	log, err := Open(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create logger: %w", err)
 	}
 
-	// Set the default logger
-	SetDefault(log)
+	// Set the default logger, closed by Shutdown
+	SetDefaultLogger(log)
+
+	// Route slog.Default and the log package through the logger
+	if cfg.SetSlogDefault {
+		addDefaultRestore(setSlogDefault(log.Logger))
+	}
+	if cfg.RedirectStdLog {
+		addDefaultRestore(RedirectStdLog(log.Logger, stdLogLevel))
+	}
+
+	// Capture call site stacks in Err only if stack traces are enabled
+	SetErrorStacks(cfg.EnableStacktrace)
 
 	// Log initialization
 	log.Info("Logger initialized",
@@ -29,6 +55,8 @@ func Init(cfg *Config) error {
 		"enableStacktrace", cfg.EnableStacktrace,
 		"environment", cfg.Environment,
 		"enableColors", cfg.EnableColors,
+		"enableTracing", cfg.EnableTracing,
+		"enableRedaction", cfg.EnableRedaction,
 	)
 
 	return nil
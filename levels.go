@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// Additional log levels, spaced like the slog levels
+const (
+	// LevelTrace is for very verbose diagnostics below debug
+	LevelTrace = slog.LevelDebug - 4
+
+	// LevelPanic is for recovered panics
+	LevelPanic = slog.LevelError + 4
+
+	// LevelFatal is for errors that terminate the program
+	LevelFatal = slog.LevelError + 8
+)
+
+// levelNames holds the names of the additional levels
+var levelNames = map[slog.Level]string{
+	LevelTrace: "TRACE",
+	LevelPanic: "PANIC",
+	LevelFatal: "FATAL",
+}
+
+// levelName returns the lowercase name of level, naming the additional levels
+func levelName(level slog.Level) string {
+	if name, ok := levelNames[level]; ok {
+		return strings.ToLower(name)
+	}
+	return strings.ToLower(level.String())
+}
+
+// replaceLevelName is a slog.HandlerOptions.ReplaceAttr function that writes
+// the names of the additional levels instead of offsets like "ERROR+4"
+func replaceLevelName(groups []string, attr slog.Attr) slog.Attr {
+	if len(groups) > 0 || attr.Key != slog.LevelKey {
+		return attr
+	}
+	if level, ok := attr.Value.Any().(slog.Level); ok {
+		if name, ok := levelNames[level]; ok {
+			attr.Value = slog.StringValue(name)
+		}
+	}
+	return attr
+}
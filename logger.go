@@ -1,10 +1,11 @@
 package logger
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
-	"os"
 	"strings"
+	"time"
 )
 
 // Config holds all the logger-related configuration
@@ -26,10 +27,99 @@ type Config struct {
 
 	// Environment is the current environment (development, staging, production)
 	Environment string `envconfig:"ENVIRONMENT" default:"production"`
+
+	// EnableTracing adds trace_id, span_id and trace_flags from the context to log output
+	EnableTracing bool `envconfig:"ENABLE_TRACING" default:"false"`
+
+	// Sinks are the names of the registered sinks records are written to (stdout, stderr, otel)
+	Sinks []string `envconfig:"SINKS" default:"stdout"`
+
+	// Backend, if set, creates the only handler records are written to instead
+	// of Sinks, such as zaplog.Backend or logruslog.Backend routing records to
+	// an existing zap core or logrus logger
+	Backend SinkFactory `ignored:"true"`
+
+	// EnableRedaction masks sensitive attribute values by key and value pattern
+	EnableRedaction bool `envconfig:"ENABLE_REDACTION" default:"false"`
+
+	// RedactKeys are the key rules used for redaction (defaults to DefaultRedactKeys)
+	RedactKeys []string `envconfig:"REDACT_KEYS"`
+
+	// RedactStrategy is how redacted values are replaced ("mask", "hash" or "truncate")
+	RedactStrategy string `envconfig:"REDACT_STRATEGY" default:"mask"`
+
+	// RedactHashKey is the HMAC key of the "hash" strategy. If it is empty,
+	// a random key is used and hashes only match within the process.
+	RedactHashKey string `envconfig:"REDACT_HASH_KEY"`
+
+	// Sampling configures record sampling by level name ("debug", "info", ...).
+	// Records at error or above are only sampled if their level is listed.
+	Sampling map[string]SamplingConfig `ignored:"true"`
+
+	// EnableAsync writes records from a background goroutine through a bounded queue
+	EnableAsync bool `envconfig:"ENABLE_ASYNC" default:"false"`
+
+	// AsyncQueueSize is the capacity of the async queue
+	AsyncQueueSize int `envconfig:"ASYNC_QUEUE_SIZE" default:"1024"`
+
+	// AsyncOverflow is the policy when the async queue is full
+	// ("block", "drop_newest", "drop_oldest" or "drop_below_level")
+	AsyncOverflow string `envconfig:"ASYNC_OVERFLOW" default:"block"`
+
+	// AsyncDropBelow is the level below which records are dropped when the
+	// async queue is full and AsyncOverflow is "drop_below_level" (defaults to "warn")
+	AsyncDropBelow string `envconfig:"ASYNC_DROP_BELOW" default:"warn"`
+
+	// EnableDedup collapses identical records into a "previous message repeated" summary
+	EnableDedup bool `envconfig:"ENABLE_DEDUP" default:"false"`
+
+	// DedupWindow is how long identical records are collapsed
+	DedupWindow time.Duration `envconfig:"DEDUP_WINDOW" default:"5s"`
+
+	// EnableFlightRecorder keeps the last records below Level in memory and
+	// writes them before the next error
+	EnableFlightRecorder bool `envconfig:"ENABLE_FLIGHT_RECORDER" default:"false"`
+
+	// FlightRecorderSize is the number of records kept by the flight recorder
+	FlightRecorderSize int `envconfig:"FLIGHT_RECORDER_SIZE" default:"256"`
+
+	// EnableMetrics counts records by level, logger name and sink, see Logger.Metrics
+	EnableMetrics bool `envconfig:"ENABLE_METRICS" default:"false"`
+
+	// MetricsExpvar is the expvar name the metrics are published under, if set
+	MetricsExpvar string `envconfig:"METRICS_EXPVAR"`
+
+	// SetSlogDefault makes Init also install the logger with slog.SetDefault
+	SetSlogDefault bool `envconfig:"SET_SLOG_DEFAULT" default:"false"`
+
+	// RedirectStdLog makes Init redirect the standard logger of the log package
+	RedirectStdLog bool `envconfig:"REDIRECT_STD_LOG" default:"false"`
+
+	// StdLogLevel is the level of records written through the log package (defaults to info)
+	StdLogLevel string `envconfig:"STD_LOG_LEVEL" default:"info"`
 }
 
-// New creates a new slog.Logger with the given configuration
+// errNewAsync is returned by New for configurations that need Close
+var errNewAsync = errors.New("async logging needs a logger that can be closed, use Open instead of New")
+
+// New creates a new slog.Logger with the given configuration.
+// Use Open instead to be able to flush and close buffered sinks. New
+// rejects EnableAsync, as its writer goroutine could never be drained.
 func New(cfg *Config) (*slog.Logger, error) {
+	if cfg.EnableAsync {
+		return nil, errNewAsync
+	}
+
+	log, err := Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return log.Logger, nil
+}
+
+// Open creates a new Logger with the given configuration, which can be
+// flushed and closed on shutdown
+func Open(cfg *Config) (*Logger, error) {
 	// Parse log level
 	level, err := parseLogLevel(cfg.Level)
 	if err != nil {
@@ -38,32 +128,172 @@ func New(cfg *Config) (*slog.Logger, error) {
 
 	// Create handler options
 	opts := &slog.HandlerOptions{
-		Level:     level,
-		AddSource: cfg.EnableCaller,
+		Level:       level,
+		AddSource:   cfg.EnableCaller,
+		ReplaceAttr: replaceLevelName,
 	}
 
-	// Create handler based on format
-	var handler slog.Handler
+	// Parse middleware options before any handler is started
+	var overflow OverflowPolicy
+	dropBelow := slog.LevelWarn
+	if cfg.EnableAsync {
+		if overflow, err = ParseOverflowPolicy(strings.ToLower(cfg.AsyncOverflow)); err != nil {
+			return nil, err
+		}
+		if cfg.AsyncDropBelow != "" {
+			if dropBelow, err = parseLogLevel(strings.ToLower(cfg.AsyncDropBelow)); err != nil {
+				return nil, fmt.Errorf("invalid async drop level: %w", err)
+			}
+		}
+	}
+
+	var redactOpts *RedactOptions
+	if cfg.EnableRedaction {
+		if redactOpts, err = newRedactOptions(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	samplingOpts, err := newSamplingOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the sink handlers
+	sinkHandlers, err := newSinkHandlers(cfg, opts)
+	if err != nil {
+		return nil, err
+	}
 
-	// Check if we should use colored output
-	useColors := cfg.EnableColors || isLocalEnvironment(cfg.Environment)
-
-	if cfg.Format == "console" && useColors {
-		// Use colored handler for console format in local environment
-		handler = NewColoredHandler(os.Stdout, opts, false)
-	} else if cfg.Format == "json" && useColors {
-		// Use colored JSON handler
-		handler = NewColoredHandler(os.Stdout, opts, true)
-	} else if cfg.Format == "console" {
-		// Use standard text handler
-		handler = slog.NewTextHandler(os.Stdout, opts)
+	// Count the records and errors of each sink
+	var metrics *Metrics
+	chain := sinkHandlers
+	if cfg.EnableMetrics {
+		metrics = NewMetrics()
+		chain = metrics.wrapSinks(sinkNames(cfg), sinkHandlers)
+	}
+
+	var handler slog.Handler
+	if len(chain) == 1 {
+		handler = chain[0]
 	} else {
-		// Use standard JSON handler
-		handler = slog.NewJSONHandler(os.Stdout, opts)
+		handler = NewMultiHandler(chain...)
+	}
+
+	// Move writes to a background goroutine
+	var async *AsyncHandler
+	if cfg.EnableAsync {
+		async = NewAsyncHandler(handler, &AsyncOptions{
+			QueueSize: cfg.AsyncQueueSize,
+			Overflow:  overflow,
+			DropBelow: dropBelow,
+			Flusher:   sinkFlusher(sinkHandlers),
+		})
+		handler = async
+	}
+
+	// Mask sensitive values, including those added from the context
+	if cfg.EnableRedaction {
+		handler = NewRedactHandler(handler, redactOpts)
+	}
+
+	// Add trace correlation attributes
+	if cfg.EnableTracing {
+		handler = NewTraceHandler(handler, nil)
+	}
+
+	// Add attributes carried by the context
+	handler = NewContextHandler(handler)
+
+	// Run the registered hooks
+	hooksHandler := NewHookHandler(handler, nil)
+	handler = hooksHandler
+
+	// Collapse repeated records
+	var dedup *DedupHandler
+	if cfg.EnableDedup {
+		dedup = NewDedupHandler(handler, &DedupOptions{Window: cfg.DedupWindow})
+		handler = dedup
+	}
+
+	// Drop sampled records before any other work is done
+	var sampling *SamplingHandler
+	if len(samplingOpts.Levels) > 0 {
+		sampling = NewSamplingHandler(handler, samplingOpts)
+		handler = sampling
+	}
+
+	// Count the records as they are logged
+	if metrics != nil {
+		metrics.collectChain(sinkNames(cfg), sinkHandlers, sampling, async)
+		handler = NewMetricsHandler(handler, &MetricsOptions{Metrics: metrics})
+
+		if cfg.MetricsExpvar != "" {
+			if err := metrics.PublishExpvar(cfg.MetricsExpvar); err != nil {
+				closeAll(sinkHandlers)
+				return nil, err
+			}
+		}
+	}
+
+	// Keep records below the level for replay on errors. The recorder is
+	// outermost so buffered records reach the hooks, dedup, sampling and
+	// metrics only when they are replayed.
+	if cfg.EnableFlightRecorder {
+		handler = NewFlightRecorderHandler(handler, &FlightRecorderOptions{Size: cfg.FlightRecorderSize})
+	}
+
+	// Create logger, emitting pending summaries, waiting for hooks and draining the async queue before the sinks
+	log := &Logger{Logger: slog.New(handler), metrics: metrics}
+	if dedup != nil {
+		log.components = append(log.components, dedup)
+	}
+	log.components = append(log.components, hooksHandler)
+	if async != nil {
+		log.components = append(log.components, async)
+	}
+	for _, sink := range sinkHandlers {
+		log.components = append(log.components, sink)
+	}
+
+	return log, nil
+}
+
+// newRedactOptions creates the redaction options from the config
+func newRedactOptions(cfg *Config) (*RedactOptions, error) {
+	strategy := RedactStrategy(strings.ToLower(cfg.RedactStrategy))
+	switch strategy {
+	case "", RedactMask, RedactHash, RedactTruncate:
+	default:
+		return nil, fmt.Errorf("unknown redaction strategy: %s", cfg.RedactStrategy)
 	}
 
-	// Create logger
-	return slog.New(handler), nil
+	keys := cfg.RedactKeys
+	if len(keys) == 0 {
+		keys = DefaultRedactKeys
+	}
+
+	return &RedactOptions{
+		Keys:     keys,
+		Patterns: DefaultRedactPatterns(),
+		Strategy: strategy,
+		HashKey:  []byte(cfg.RedactHashKey),
+	}, nil
+}
+
+// newSamplingOptions creates the sampling options from the config
+func newSamplingOptions(cfg *Config) (*SamplingOptions, error) {
+	opts := &SamplingOptions{Levels: make(map[slog.Level]Sampler, len(cfg.Sampling))}
+	for name, samplingCfg := range cfg.Sampling {
+		level, err := parseLogLevel(strings.ToLower(name))
+		if err != nil {
+			return nil, fmt.Errorf("invalid sampling level: %w", err)
+		}
+		if sampler := samplingCfg.Sampler(); sampler != nil {
+			opts.Levels[level] = sampler
+		}
+	}
+	return opts, nil
 }
 
 // isLocalEnvironment checks if the environment is a local/development environment
@@ -75,6 +305,8 @@ func isLocalEnvironment(env string) bool {
 // parseLogLevel parses a log level string into a slog.Level
 func parseLogLevel(level string) (slog.Level, error) {
 	switch level {
+	case "trace":
+		return LevelTrace, nil
 	case "debug":
 		return slog.LevelDebug, nil
 	case "info":
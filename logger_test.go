@@ -60,6 +60,7 @@ func TestParseLogLevel(t *testing.T) {
 		expected slog.Level
 		hasError bool
 	}{
+		{"trace", LevelTrace, false},
 		{"debug", slog.LevelDebug, false},
 		{"info", slog.LevelInfo, false},
 		{"warn", slog.LevelWarn, false},
@@ -0,0 +1,180 @@
+package logruslog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"sync"
+
+	"github.com/legrch/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// HandlerOptions configures a Handler
+type HandlerOptions struct {
+	// Level is the minimum enabled logging level, in addition to the level of the logger
+	Level slog.Leveler
+
+	// AddSource sets the caller of entries, which the formatter writes if
+	// the logger has ReportCaller set
+	AddSource bool
+}
+
+// Handler is a slog.Handler that writes records to a *logrus.Logger.
+// Attributes in groups become fields with dotted keys.
+//
+// Entries go through the hooks, formatter and output of the logger like
+// entries logged with logrus, but a record at logger.LevelPanic or
+// logger.LevelFatal never panics or exits. Writes are serialized between the
+// handlers derived from one Handler, not with the logrus logger itself.
+type Handler struct {
+	logger    *logrus.Logger
+	level     slog.Leveler
+	addSource bool
+	mu        *sync.Mutex
+	fields    logrus.Fields
+	prefix    string
+}
+
+// NewHandler creates a Handler writing to l
+func NewHandler(l *logrus.Logger, opts *HandlerOptions) *Handler {
+	if opts == nil {
+		opts = &HandlerOptions{}
+	}
+	return &Handler{
+		logger:    l,
+		level:     opts.Level,
+		addSource: opts.AddSource,
+		mu:        &sync.Mutex{},
+		fields:    logrus.Fields{},
+	}
+}
+
+// Backend returns a logger.SinkFactory for Config.Backend, writing records
+// to l with the level and source settings of the config
+func Backend(l *logrus.Logger) logger.SinkFactory {
+	return func(_ *logger.Config, opts *slog.HandlerOptions) (slog.Handler, error) {
+		return NewHandler(l, &HandlerOptions{Level: opts.Level, AddSource: opts.AddSource}), nil
+	}
+}
+
+// LogrusLevel converts a slog level to a logrus level
+func LogrusLevel(level slog.Level) logrus.Level {
+	switch {
+	case level < slog.LevelDebug:
+		return logrus.TraceLevel
+	case level < slog.LevelInfo:
+		return logrus.DebugLevel
+	case level < slog.LevelWarn:
+		return logrus.InfoLevel
+	case level < slog.LevelError:
+		return logrus.WarnLevel
+	case level < logger.LevelPanic:
+		return logrus.ErrorLevel
+	case level < logger.LevelFatal:
+		return logrus.PanicLevel
+	default:
+		return logrus.FatalLevel
+	}
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	if h.level != nil && level < h.level.Level() {
+		return false
+	}
+	return h.logger.IsLevelEnabled(LogrusLevel(level))
+}
+
+// Handle implements slog.Handler.
+//
+//nolint:gocritic // Cannot change signature due to interface contract
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	fields := make(logrus.Fields, len(h.fields)+r.NumAttrs())
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	r.Attrs(func(attr slog.Attr) bool {
+		addField(fields, h.prefix, attr)
+		return true
+	})
+
+	entry := logrus.NewEntry(h.logger).WithContext(ctx).WithTime(r.Time).WithFields(fields)
+	entry.Level = LogrusLevel(r.Level)
+	entry.Message = r.Message
+	if h.addSource && r.PC != 0 {
+		f, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		entry.Caller = &f
+	}
+
+	// Entry.Log would panic at PanicLevel and replace the caller, so the
+	// steps of logrus are repeated here
+	if err := h.logger.Hooks.Fire(entry.Level, entry); err != nil {
+		return fmt.Errorf("logruslog: fire hooks: %w", err)
+	}
+	if !h.logger.ReportCaller {
+		entry.Caller = nil
+	}
+
+	b, err := h.logger.Formatter.Format(entry)
+	if err != nil {
+		return fmt.Errorf("logruslog: format entry: %w", err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, err := h.logger.Out.Write(b); err != nil {
+		return fmt.Errorf("logruslog: write entry: %w", err)
+	}
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	h2 := *h
+	h2.fields = make(logrus.Fields, len(h.fields)+len(attrs))
+	for k, v := range h.fields {
+		h2.fields[k] = v
+	}
+	for _, attr := range attrs {
+		addField(h2.fields, h.prefix, attr)
+	}
+	return &h2
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+// addField adds attr to fields under prefix, flattening groups into dotted
+// keys and ignoring empty attributes like slog handlers
+func addField(fields logrus.Fields, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if attr.Key != "" {
+			groupPrefix = prefix + attr.Key + "."
+		}
+		for _, a := range attr.Value.Group() {
+			addField(fields, groupPrefix, a)
+		}
+		return
+	}
+
+	fields[prefix+attr.Key] = attr.Value.Any()
+}
@@ -0,0 +1,106 @@
+package logruslog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/legrch/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHook keeps the entries it fires for
+type recordingHook struct {
+	entries []*logrus.Entry
+}
+
+func (*recordingHook) Levels() []logrus.Level { return logrus.AllLevels }
+
+func (h *recordingHook) Fire(e *logrus.Entry) error {
+	h.entries = append(h.entries, e)
+	return nil
+}
+
+func newLogrus(buf *bytes.Buffer) (*logrus.Logger, *recordingHook) {
+	l := logrus.New()
+	l.SetOutput(buf)
+	l.SetFormatter(&logrus.JSONFormatter{})
+	l.SetLevel(logrus.DebugLevel)
+	l.SetReportCaller(true)
+
+	hook := &recordingHook{}
+	l.AddHook(hook)
+	return l, hook
+}
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	l, hook := newLogrus(&buf)
+	log := slog.New(NewHandler(l, &HandlerOptions{AddSource: true}))
+
+	log.With("service", "api").
+		WithGroup("req").
+		With("id", "r1").
+		Warn("slow", "took_ms", 120, slog.Group("user", "name", "alice"), "err", errors.New("timeout"))
+
+	require.Len(t, hook.entries, 1)
+	e := hook.entries[0]
+	assert.Equal(t, logrus.WarnLevel, e.Level)
+	assert.Equal(t, "slow", e.Message)
+	assert.Equal(t, logrus.Fields{
+		"service":       "api",
+		"req.id":        "r1",
+		"req.took_ms":   int64(120),
+		"req.user.name": "alice",
+		"req.err":       errors.New("timeout"),
+	}, e.Data)
+	require.NotNil(t, e.Caller)
+	assert.Equal(t, "github.com/legrch/logger/logruslog.TestHandler", e.Caller.Function)
+
+	assert.Contains(t, buf.String(), `"req.err":"timeout"`)
+	assert.Contains(t, buf.String(), `"func":"github.com/legrch/logger/logruslog.TestHandler"`)
+}
+
+func TestHandlerLevels(t *testing.T) {
+	var buf bytes.Buffer
+	l, hook := newLogrus(&buf)
+	h := NewHandler(l, nil)
+
+	assert.False(t, h.Enabled(context.Background(), logger.LevelTrace))
+	assert.True(t, h.Enabled(context.Background(), slog.LevelDebug))
+
+	log := slog.New(h)
+	assert.NotPanics(t, func() { log.Log(context.Background(), logger.LevelPanic, "panicked") })
+	log.Log(context.Background(), logger.LevelFatal, "fatal")
+
+	require.Len(t, hook.entries, 2)
+	assert.Equal(t, logrus.PanicLevel, hook.entries[0].Level)
+	assert.Equal(t, logrus.FatalLevel, hook.entries[1].Level)
+	assert.Nil(t, hook.entries[0].Caller)
+}
+
+func TestBackend(t *testing.T) {
+	var buf bytes.Buffer
+	l, _ := newLogrus(&buf)
+
+	log, err := logger.Open(&logger.Config{
+		Level:           "info",
+		EnableRedaction: true,
+		Backend:         Backend(l),
+	})
+	require.NoError(t, err)
+
+	ctx := logger.ContextWithAttrs(context.Background(), slog.String("request_id", "r1"))
+	log.DebugContext(ctx, "hidden")
+	log.InfoContext(ctx, "login", "password", "hunter2")
+
+	out := buf.String()
+	assert.NotContains(t, out, "hidden")
+	assert.Contains(t, out, `"msg":"login"`)
+	assert.Contains(t, out, `"request_id":"r1"`)
+	assert.NotContains(t, out, "hunter2")
+}
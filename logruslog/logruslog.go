@@ -0,0 +1,155 @@
+// Package logruslog provides a *logrus.Logger, which implements
+// logrus.FieldLogger, that writes entries to a slog.Handler, so code using
+// logrus logs through the handler chain configured by logger.New or
+// logger.Open:
+//
+//	log, _ := logger.Open(cfg)
+//	var fl logrus.FieldLogger = logruslog.New(log.Handler(), &logruslog.Options{Flusher: log})
+//	fl.WithField("addr", addr).Info("connected")
+package logruslog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/legrch/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// Options configures the logger returned by New
+type Options struct {
+	// Flusher is flushed before logrus panics or exits, typically the
+	// *logger.Logger returned by Open
+	Flusher logger.Flusher
+
+	// AddSource records the caller of logrus, for handlers adding the source
+	AddSource bool
+}
+
+// maxCallerDepth bounds the stack searched for the caller of logrus
+const maxCallerDepth = 32
+
+// Formatter is a logrus.Formatter that writes entries to a slog.Handler
+// instead of formatting them
+type Formatter struct {
+	handler   slog.Handler
+	flusher   logger.Flusher
+	addSource bool
+}
+
+// NewFormatter creates a Formatter writing to h
+func NewFormatter(h slog.Handler, opts *Options) *Formatter {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return &Formatter{handler: h, flusher: opts.Flusher, addSource: opts.AddSource}
+}
+
+// New creates a *logrus.Logger writing to h. Its level is TraceLevel so
+// that filtering is left to the handler.
+func New(h slog.Handler, opts *Options) *logrus.Logger {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	l.SetFormatter(NewFormatter(h, opts))
+	l.SetLevel(logrus.TraceLevel)
+	return l
+}
+
+// Level converts a logrus level to a slog level
+func Level(level logrus.Level) slog.Level {
+	switch level {
+	case logrus.PanicLevel:
+		return logger.LevelPanic
+	case logrus.FatalLevel:
+		return logger.LevelFatal
+	case logrus.ErrorLevel:
+		return slog.LevelError
+	case logrus.WarnLevel:
+		return slog.LevelWarn
+	case logrus.InfoLevel:
+		return slog.LevelInfo
+	case logrus.DebugLevel:
+		return slog.LevelDebug
+	default:
+		return logger.LevelTrace
+	}
+}
+
+// Format implements logrus.Formatter. It handles the entry with the entry
+// context, logging fields in key order and error values like logger.Err,
+// and returns no output.
+func (f *Formatter) Format(entry *logrus.Entry) ([]byte, error) {
+	ctx := entry.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	level := Level(entry.Level)
+	if !f.handler.Enabled(ctx, level) {
+		return nil, nil
+	}
+
+	var pc uintptr
+	if f.addSource {
+		pc = callerPC()
+	}
+
+	r := slog.NewRecord(entry.Time, level, entry.Message, pc)
+	keys := make([]string, 0, len(entry.Data))
+	for k := range entry.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		r.AddAttrs(fieldToAttr(k, entry.Data[k]))
+	}
+
+	if err := f.handler.Handle(ctx, r); err != nil {
+		return nil, fmt.Errorf("logruslog: handle entry: %w", err)
+	}
+
+	// logrus panics or exits after writing these
+	if entry.Level <= logrus.FatalLevel && f.flusher != nil {
+		if err := f.flusher.Flush(ctx); err != nil {
+			return nil, fmt.Errorf("logruslog: flush: %w", err)
+		}
+	}
+
+	return nil, nil
+}
+
+// fieldToAttr converts a logrus field to an attribute
+func fieldToAttr(key string, value any) slog.Attr {
+	if err, ok := value.(error); ok && err != nil {
+		return slog.Attr{Key: key, Value: logger.ErrValue(err)}
+	}
+	return slog.Any(key, value)
+}
+
+// callerPC returns the program counter of the function that called logrus,
+// found as the first frame after the logrus frames above Format
+func callerPC() uintptr {
+	var pcs [maxCallerDepth]uintptr
+	n := runtime.Callers(3, pcs[:])
+
+	for _, pc := range pcs[:n] {
+		fn := runtime.FuncForPC(pc - 1)
+		if fn == nil {
+			continue
+		}
+		if strings.HasPrefix(fn.Name(), "github.com/sirupsen/logrus.") {
+			continue
+		}
+		return pc
+	}
+	return 0
+}
@@ -0,0 +1,105 @@
+package logruslog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/legrch/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decode(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var m map[string]any
+		require.NoError(t, dec.Decode(&m))
+		records = append(records, m)
+	}
+	return records
+}
+
+// flushCounter counts Flush calls
+type flushCounter struct{ n int }
+
+func (f *flushCounter) Flush(context.Context) error {
+	f.n++
+	return nil
+}
+
+func TestFieldLogger(t *testing.T) {
+	var buf bytes.Buffer
+	h := slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true})
+
+	var fl logrus.FieldLogger = New(h, &Options{AddSource: true})
+	fl.WithFields(logrus.Fields{"pool": 4, "addr": "localhost"}).
+		WithError(errors.New("refused")).
+		Warnf("retry %d", 2)
+
+	records := decode(t, &buf)
+	require.Len(t, records, 1)
+	r := records[0]
+	assert.Equal(t, "WARN", r["level"])
+	assert.Equal(t, "retry 2", r["msg"])
+	assert.Equal(t, "localhost", r["addr"])
+	assert.InDelta(t, 4, r["pool"], 0)
+
+	errGroup, ok := r[logrus.ErrorKey].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "refused", errGroup[logger.ErrorMessageKey])
+
+	source, ok := r["source"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "github.com/legrch/logger/logruslog.TestFieldLogger", source["function"])
+}
+
+func TestFieldOrderAndContext(t *testing.T) {
+	mock := &logger.MockLogger{}
+	l := New(logger.NewContextHandler(mock), nil)
+
+	ctx := logger.ContextWithAttrs(context.Background(), slog.String("request_id", "r1"))
+	l.WithContext(ctx).WithFields(logrus.Fields{"b": 2, "a": 1}).Info("sorted")
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 1)
+	keys := make([]string, 0, len(logs[0].Attrs))
+	for _, attr := range logs[0].Attrs {
+		keys = append(keys, attr.Key)
+	}
+	assert.Equal(t, []string{"a", "b", "request_id"}, keys)
+}
+
+func TestLevels(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}), nil)
+
+	l.Debug("hidden")
+	l.Trace("hidden")
+	l.Error("shown")
+	assert.Len(t, decode(t, &buf), 1)
+
+	assert.Equal(t, logger.LevelTrace, Level(logrus.TraceLevel))
+	assert.Equal(t, logger.LevelPanic, Level(logrus.PanicLevel))
+	assert.Equal(t, logger.LevelFatal, Level(logrus.FatalLevel))
+}
+
+func TestPanicFlushes(t *testing.T) {
+	flusher := &flushCounter{}
+	mock := &logger.MockLogger{}
+	l := New(mock, &Options{Flusher: flusher})
+
+	assert.Panics(t, func() { l.Panic("bad") })
+	assert.Equal(t, 1, flusher.n)
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 1)
+	assert.Equal(t, logger.LevelPanic, logs[0].Level)
+}
@@ -0,0 +1,419 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// LoggerNameKey is the attribute key that names a logger in metrics, set with
+// logger.With(LoggerNameKey, "db")
+const LoggerNameKey = "logger"
+
+// Metric names
+const (
+	MetricRecords       = "logger_records_total"
+	MetricHandlerErrors = "logger_handler_errors_total"
+	MetricSinkRecords   = "logger_sink_records_total"
+	MetricSinkErrors    = "logger_sink_errors_total"
+	MetricSinkBytes     = "logger_sink_bytes_total"
+	MetricDropped       = "logger_dropped_records_total"
+)
+
+// metricHelp holds the help text of each metric
+var metricHelp = map[string]string{
+	MetricRecords:       "Number of records logged, by level and logger name.",
+	MetricHandlerErrors: "Number of errors returned by the handler chain, by logger name.",
+	MetricSinkRecords:   "Number of records passed to a sink, by sink and level.",
+	MetricSinkErrors:    "Number of errors returned by a sink.",
+	MetricSinkBytes:     "Number of bytes written by a sink.",
+	MetricDropped:       "Number of records dropped before reaching the sinks, by reason.",
+}
+
+// ByteCounter is implemented by sinks that count the bytes they write
+type ByteCounter interface {
+	// BytesWritten returns the number of bytes written so far
+	BytesWritten() uint64
+}
+
+// metricKey identifies a counter by name and formatted labels
+type metricKey struct {
+	name   string
+	labels string
+}
+
+// metricSample is a counter value reported by a collector
+type metricSample struct {
+	metricKey
+	value uint64
+}
+
+// Metrics holds the counters of the metrics handlers of a logger. It serves
+// them in the Prometheus text exposition format and can be published with expvar.
+type Metrics struct {
+	mu         sync.RWMutex
+	counters   map[metricKey]*atomic.Uint64
+	collectors []func() []metricSample
+}
+
+// NewMetrics creates an empty Metrics
+func NewMetrics() *Metrics {
+	return &Metrics{counters: make(map[metricKey]*atomic.Uint64)}
+}
+
+// counter returns the counter for name and labels, creating it if needed
+func (m *Metrics) counter(name, labels string) *atomic.Uint64 {
+	key := metricKey{name: name, labels: labels}
+
+	m.mu.RLock()
+	c, ok := m.counters[key]
+	m.mu.RUnlock()
+	if ok {
+		return c
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c, ok = m.counters[key]; !ok {
+		c = &atomic.Uint64{}
+		m.counters[key] = c
+	}
+	return c
+}
+
+// collect registers a function reporting counters kept elsewhere, read on every scrape
+func (m *Metrics) collect(fn func() []metricSample) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.collectors = append(m.collectors, fn)
+}
+
+// snapshot returns all counter values, sorted by name and labels
+func (m *Metrics) snapshot() []metricSample {
+	m.mu.RLock()
+	samples := make([]metricSample, 0, len(m.counters))
+	for key, c := range m.counters {
+		samples = append(samples, metricSample{metricKey: key, value: c.Load()})
+	}
+	collectors := m.collectors
+	m.mu.RUnlock()
+
+	for _, fn := range collectors {
+		samples = append(samples, fn()...)
+	}
+
+	sort.Slice(samples, func(i, j int) bool {
+		if samples[i].name != samples[j].name {
+			return samples[i].name < samples[j].name
+		}
+		return samples[i].labels < samples[j].labels
+	})
+	return samples
+}
+
+// Value returns the value of the counter with the given name and label pairs
+func (m *Metrics) Value(name string, labelPairs ...string) uint64 {
+	labels := formatLabels(labelPairs...)
+	for _, s := range m.snapshot() {
+		if s.name == name && s.labels == labels {
+			return s.value
+		}
+	}
+	return 0
+}
+
+// WriteTo writes the counters in the Prometheus text exposition format
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+
+	last := ""
+	for _, s := range m.snapshot() {
+		if s.name != last {
+			fmt.Fprintf(&buf, "# HELP %s %s\n# TYPE %s counter\n", s.name, metricHelp[s.name], s.name)
+			last = s.name
+		}
+		if s.labels == "" {
+			fmt.Fprintf(&buf, "%s %d\n", s.name, s.value)
+		} else {
+			fmt.Fprintf(&buf, "%s{%s} %d\n", s.name, s.labels, s.value)
+		}
+	}
+
+	return buf.WriteTo(w)
+}
+
+// ServeHTTP implements http.Handler, serving the Prometheus text exposition format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = m.WriteTo(w)
+}
+
+// String implements expvar.Var, returning the counters as JSON keyed by name and labels
+func (m *Metrics) String() string {
+	out := make(map[string]map[string]uint64)
+	for _, s := range m.snapshot() {
+		if out[s.name] == nil {
+			out[s.name] = make(map[string]uint64)
+		}
+		out[s.name][s.labels] = s.value
+	}
+
+	b, err := json.Marshal(out)
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
+
+var (
+	// expvarMetrics holds the published expvar variables by name
+	expvarMetrics = map[string]*metricsVar{}
+
+	// expvarMu protects expvarMetrics
+	expvarMu sync.Mutex
+)
+
+// metricsVar is a published expvar variable pointing to the current Metrics
+type metricsVar struct {
+	m atomic.Pointer[Metrics]
+}
+
+// String implements expvar.Var.
+func (v *metricsVar) String() string {
+	return v.m.Load().String()
+}
+
+// PublishExpvar publishes the counters as an expvar variable. Publishing
+// another Metrics under the same name replaces the previous one, so loggers
+// can be reopened. It returns an error if the name is used by another variable.
+func (m *Metrics) PublishExpvar(name string) error {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+
+	if v, ok := expvarMetrics[name]; ok {
+		v.m.Store(m)
+		return nil
+	}
+	if expvar.Get(name) != nil {
+		return fmt.Errorf("expvar %q is already published", name)
+	}
+
+	v := &metricsVar{}
+	v.m.Store(m)
+	expvar.Publish(name, v)
+	expvarMetrics[name] = v
+
+	return nil
+}
+
+// formatLabels formats key/value pairs as Prometheus labels
+func formatLabels(pairs ...string) string {
+	var b strings.Builder
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i])
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(pairs[i+1]))
+		b.WriteByte('"')
+	}
+	return b.String()
+}
+
+// labelEscaper escapes label values for the text exposition format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// MetricsOptions configures a MetricsHandler
+type MetricsOptions struct {
+	// Metrics receives the counters, defaults to a new Metrics
+	Metrics *Metrics
+
+	// Sink names the sink the handler wraps. Without a sink the handler
+	// counts records by level and logger name.
+	Sink string
+}
+
+// metricsCounters caches the counters of one handler and its derived handlers
+type metricsCounters struct {
+	name    string
+	levels  sync.Map // map[slog.Level]*atomic.Uint64
+	errors  *atomic.Uint64
+	grouped bool
+}
+
+// MetricsHandler is a slog.Handler that counts the records and errors
+// passing through it.
+type MetricsHandler struct {
+	next     slog.Handler
+	metrics  *Metrics
+	sink     string
+	counters *metricsCounters
+}
+
+// NewMetricsHandler creates a new MetricsHandler wrapping next.
+func NewMetricsHandler(next slog.Handler, opts *MetricsOptions) *MetricsHandler {
+	if opts == nil {
+		opts = &MetricsOptions{}
+	}
+
+	m := opts.Metrics
+	if m == nil {
+		m = NewMetrics()
+	}
+
+	h := &MetricsHandler{next: next, metrics: m, sink: opts.Sink}
+	h.counters = h.newCounters("", false)
+
+	return h
+}
+
+// Metrics returns the counters the handler records to
+func (h *MetricsHandler) Metrics() *Metrics {
+	return h.metrics
+}
+
+// Enabled implements slog.Handler.
+func (h *MetricsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+//
+//nolint:gocritic // Cannot change signature due to interface contract
+func (h *MetricsHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.levelCounter(r.Level).Add(1)
+
+	err := h.next.Handle(ctx, r)
+	if err != nil {
+		h.counters.errors.Add(1)
+	}
+	return err
+}
+
+// WithAttrs implements slog.Handler.
+func (h *MetricsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.next = h.next.WithAttrs(attrs)
+
+	// A logger name outside of groups starts a new set of counters
+	if !h.counters.grouped && h.sink == "" {
+		for _, attr := range attrs {
+			if attr.Key == LoggerNameKey {
+				h2.counters = h.newCounters(attr.Value.Resolve().String(), false)
+			}
+		}
+	}
+	return &h2
+}
+
+// WithGroup implements slog.Handler.
+func (h *MetricsHandler) WithGroup(name string) slog.Handler {
+	h2 := *h
+	h2.next = h.next.WithGroup(name)
+	if !h.counters.grouped {
+		h2.counters = h.newCounters(h.counters.name, true)
+	}
+	return &h2
+}
+
+// newCounters creates the counter cache for a logger name
+func (h *MetricsHandler) newCounters(name string, grouped bool) *metricsCounters {
+	c := &metricsCounters{name: name, grouped: grouped}
+	if h.sink != "" {
+		c.errors = h.metrics.counter(MetricSinkErrors, formatLabels("sink", h.sink))
+	} else {
+		c.errors = h.metrics.counter(MetricHandlerErrors, formatLabels("logger", name))
+	}
+	return c
+}
+
+// levelCounter returns the record counter for level
+func (h *MetricsHandler) levelCounter(level slog.Level) *atomic.Uint64 {
+	c := h.counters
+	if counter, ok := c.levels.Load(level); ok {
+		return counter.(*atomic.Uint64)
+	}
+
+	var counter *atomic.Uint64
+	if h.sink != "" {
+		counter = h.metrics.counter(MetricSinkRecords, formatLabels("sink", h.sink, "level", levelName(level)))
+	} else {
+		counter = h.metrics.counter(MetricRecords, formatLabels("level", levelName(level), "logger", c.name))
+	}
+	actual, _ := c.levels.LoadOrStore(level, counter)
+	return actual.(*atomic.Uint64)
+}
+
+// countingWriter counts the bytes written to an io.Writer
+type countingWriter struct {
+	w       io.Writer
+	written atomic.Uint64
+}
+
+// Write implements io.Writer.
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.written.Add(uint64(max(n, 0)))
+	return n, err
+}
+
+// writerHandler is a sink handler writing to a countingWriter
+type writerHandler struct {
+	slog.Handler
+	w *countingWriter
+}
+
+// BytesWritten implements ByteCounter.
+func (h *writerHandler) BytesWritten() uint64 {
+	return h.w.written.Load()
+}
+
+// wrapSinks wraps each sink with a metrics handler labeled with its name
+func (m *Metrics) wrapSinks(names []string, sinks []slog.Handler) []slog.Handler {
+	wrapped := make([]slog.Handler, len(sinks))
+	for i, sink := range sinks {
+		wrapped[i] = NewMetricsHandler(sink, &MetricsOptions{Metrics: m, Sink: names[i]})
+	}
+	return wrapped
+}
+
+// collectChain registers a collector for the bytes written by the sinks and
+// the records dropped by the sampling and async handlers, which may be nil
+func (m *Metrics) collectChain(names []string, sinks []slog.Handler, sampling *SamplingHandler, async *AsyncHandler) {
+	m.collect(func() []metricSample {
+		var samples []metricSample
+		for i, sink := range sinks {
+			if c, ok := sink.(ByteCounter); ok {
+				samples = append(samples, metricSample{
+					metricKey: metricKey{name: MetricSinkBytes, labels: formatLabels("sink", names[i])},
+					value:     c.BytesWritten(),
+				})
+			}
+		}
+		if sampling != nil {
+			for level, n := range sampling.Stats().DroppedByLevel {
+				samples = append(samples, metricSample{
+					metricKey: metricKey{name: MetricDropped, labels: formatLabels("reason", "sampling", "level", levelName(level))},
+					value:     n,
+				})
+			}
+		}
+		if async != nil {
+			samples = append(samples, metricSample{
+				metricKey: metricKey{name: MetricDropped, labels: formatLabels("reason", "async_overflow")},
+				value:     async.Stats().Dropped,
+			})
+		}
+		return samples
+	})
+}
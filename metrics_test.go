@@ -0,0 +1,142 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingSink is a MockLogger whose Handle always fails
+type failingSink struct {
+	*MockLogger
+}
+
+func (failingSink) Handle(context.Context, slog.Record) error {
+	return errors.New("write failed")
+}
+
+func TestMetricsHandler(t *testing.T) {
+	handler := NewMetricsHandler(&MockLogger{}, nil)
+	m := handler.Metrics()
+	log := slog.New(handler)
+
+	log.Info("one")
+	log.Info("two")
+	log.Error("three")
+	log.Log(context.Background(), LevelTrace, "four")
+
+	db := log.With(LoggerNameKey, "db")
+	db.Warn("slow query")
+	db.WithGroup("query").With(LoggerNameKey, "ignored").Warn("slow query")
+
+	assert.Equal(t, uint64(2), m.Value(MetricRecords, "level", "info", "logger", ""))
+	assert.Equal(t, uint64(1), m.Value(MetricRecords, "level", "error", "logger", ""))
+	assert.Equal(t, uint64(1), m.Value(MetricRecords, "level", "trace", "logger", ""))
+	assert.Equal(t, uint64(2), m.Value(MetricRecords, "level", "warn", "logger", "db"))
+	assert.Equal(t, uint64(0), m.Value(MetricRecords, "level", "warn", "logger", "ignored"))
+}
+
+func TestMetricsHandlerSinkErrors(t *testing.T) {
+	m := NewMetrics()
+	handler := NewMetricsHandler(failingSink{&MockLogger{}}, &MetricsOptions{Metrics: m, Sink: "file"})
+
+	require.Error(t, handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "msg", 0)))
+
+	assert.Equal(t, uint64(1), m.Value(MetricSinkRecords, "sink", "file", "level", "info"))
+	assert.Equal(t, uint64(1), m.Value(MetricSinkErrors, "sink", "file"))
+}
+
+func TestMetricsExposition(t *testing.T) {
+	handler := NewMetricsHandler(&MockLogger{}, nil)
+	log := slog.New(handler)
+	log.Info("one")
+	log.With(LoggerNameKey, `say "hi"`).Error("two")
+
+	rec := httptest.NewRecorder()
+	handler.Metrics().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain; version=0.0.4")
+	assert.Equal(t, strings.Join([]string{
+		"# HELP logger_handler_errors_total Number of errors returned by the handler chain, by logger name.",
+		"# TYPE logger_handler_errors_total counter",
+		`logger_handler_errors_total{logger=""} 0`,
+		`logger_handler_errors_total{logger="say \"hi\""} 0`,
+		"# HELP logger_records_total Number of records logged, by level and logger name.",
+		"# TYPE logger_records_total counter",
+		`logger_records_total{level="error",logger="say \"hi\""} 1`,
+		`logger_records_total{level="info",logger=""} 1`,
+		"",
+	}, "\n"), rec.Body.String())
+}
+
+// expvarRuns makes the expvar names of each test run unique, as the
+// registry is process-wide and cannot be cleared
+var expvarRuns atomic.Int64
+
+// expvarName returns a variable name not published by a previous run
+func expvarName(t *testing.T, suffix string) string {
+	return fmt.Sprintf("%s_%d_%s", t.Name(), expvarRuns.Add(1), suffix)
+}
+
+func TestMetricsExpvar(t *testing.T) {
+	name := expvarName(t, "metrics")
+	m1 := NewMetrics()
+	slog.New(NewMetricsHandler(&MockLogger{}, &MetricsOptions{Metrics: m1})).Info("one")
+	require.NoError(t, m1.PublishExpvar(name))
+
+	var got map[string]map[string]uint64
+	require.NoError(t, json.Unmarshal([]byte(expvar.Get(name).String()), &got))
+	assert.Equal(t, uint64(1), got[MetricRecords][`level="info",logger=""`])
+
+	// Publishing again under the same name replaces the metrics
+	m2 := NewMetrics()
+	require.NoError(t, m2.PublishExpvar(name))
+	assert.Equal(t, "{}", expvar.Get(name).String())
+
+	taken := expvarName(t, "taken")
+	expvar.NewInt(taken)
+	assert.Error(t, m2.PublishExpvar(taken))
+}
+
+func TestOpenMetrics(t *testing.T) {
+	log, err := Open(&Config{
+		Level:         "info",
+		Format:        FormatJSON,
+		Sinks:         []string{SinkStderr},
+		EnableMetrics: true,
+		EnableAsync:   true,
+		Sampling:      map[string]SamplingConfig{"info": {Initial: 1}},
+	})
+	require.NoError(t, err)
+
+	log.Info("sampled")
+	log.Info("sampled")
+	log.Error("failed")
+	require.NoError(t, log.Close(context.Background()))
+
+	m := log.Metrics()
+	require.NotNil(t, m)
+	assert.Equal(t, uint64(2), m.Value(MetricRecords, "level", "info", "logger", ""))
+	assert.Equal(t, uint64(1), m.Value(MetricDropped, "reason", "sampling", "level", "info"))
+	assert.Equal(t, uint64(0), m.Value(MetricDropped, "reason", "async_overflow"))
+	assert.Equal(t, uint64(1), m.Value(MetricSinkRecords, "sink", "stderr", "level", "info"))
+	assert.Equal(t, uint64(1), m.Value(MetricSinkRecords, "sink", "stderr", "level", "error"))
+	assert.Positive(t, m.Value(MetricSinkBytes, "sink", "stderr"))
+
+	plain, err := Open(&Config{Level: "info", Format: FormatJSON})
+	require.NoError(t, err)
+	assert.Nil(t, plain.Metrics())
+}
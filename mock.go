@@ -4,31 +4,52 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"runtime"
+	"strings"
 	"sync"
+	"time"
 )
 
-// MockLogger is a mock implementation of slog.Handler for testing
+// MockLogger is a mock implementation of slog.Handler for testing.
+// The handlers derived with WithAttrs and WithGroup record their entries
+// in the same store, so they are all returned by GetLogs.
 type MockLogger struct {
-	mu     sync.Mutex
-	logs   []LogEntry
+	mu  sync.Mutex
+	rec *mockRecorder
+
 	attrs  []slog.Attr
+	paths  [][]string
 	groups []string
 }
 
+// mockRecorder stores the entries of a MockLogger and its derived handlers
+type mockRecorder struct {
+	mu   sync.Mutex
+	logs []LogEntry
+}
+
 // LogEntry represents a log entry
 type LogEntry struct {
+	Time    time.Time
 	Level   slog.Level
 	Message string
 	Attrs   []slog.Attr
+
+	// Groups holds the group path of each of Attrs, opened with WithGroup
+	Groups [][]string
+
+	// Source is the location of the log call, nil if the record has no caller
+	Source *slog.Source
+
+	// Context is the context the record was logged with, and ContextAttrs
+	// the attributes added to it with ContextWithAttrs
+	Context      context.Context
+	ContextAttrs []slog.Attr
 }
 
 // NewMockLogger creates a new mock logger
 func NewMockLogger() *slog.Logger {
-	mock := &MockLogger{
-		logs:  make([]LogEntry, 0),
-		attrs: make([]slog.Attr, 0),
-	}
-	return slog.New(mock)
+	return slog.New(&MockLogger{rec: &mockRecorder{}})
 }
 
 // Enabled implements slog.Handler.
@@ -39,24 +60,33 @@ func (*MockLogger) Enabled(_ context.Context, _ slog.Level) bool {
 // Handle implements slog.Handler.
 //
 //nolint:gocritic // Cannot change signature due to interface contract
-func (l *MockLogger) Handle(_ context.Context, r slog.Record) error {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
-	// Collect all attributes
-	attrs := make([]slog.Attr, len(l.attrs))
+func (l *MockLogger) Handle(ctx context.Context, r slog.Record) error {
+	// Collect all attributes with their group paths
+	attrs := make([]slog.Attr, len(l.attrs), len(l.attrs)+r.NumAttrs())
 	copy(attrs, l.attrs)
+	paths := make([][]string, len(l.paths), len(l.paths)+r.NumAttrs())
+	copy(paths, l.paths)
 
 	r.Attrs(func(attr slog.Attr) bool {
 		attrs = append(attrs, attr)
+		paths = append(paths, l.groups)
 		return true
 	})
 
 	// Add the log entry
-	l.logs = append(l.logs, LogEntry{
-		Level:   r.Level,
-		Message: r.Message,
-		Attrs:   attrs,
+	rec := l.recorder()
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	rec.logs = append(rec.logs, LogEntry{
+		Time:         r.Time,
+		Level:        r.Level,
+		Message:      r.Message,
+		Attrs:        attrs,
+		Groups:       paths,
+		Source:       recordSource(r.PC),
+		Context:      ctx,
+		ContextAttrs: AttrsFromContext(ctx),
 	})
 
 	return nil
@@ -64,36 +94,42 @@ func (l *MockLogger) Handle(_ context.Context, r slog.Record) error {
 
 // WithAttrs implements slog.Handler.
 func (l *MockLogger) WithAttrs(attrs []slog.Attr) slog.Handler {
-	l.mu.Lock()
-	defer l.mu.Unlock()
+	if len(attrs) == 0 {
+		return l
+	}
 
-	// Create a new logger that shares the logs slice
+	// Create a new logger that shares the recorder
 	newLogger := &MockLogger{
-		logs:   l.logs,
-		attrs:  make([]slog.Attr, len(l.attrs)+len(attrs)),
-		groups: make([]string, len(l.groups)),
+		rec:    l.recorder(),
+		attrs:  make([]slog.Attr, len(l.attrs), len(l.attrs)+len(attrs)),
+		paths:  make([][]string, len(l.paths), len(l.paths)+len(attrs)),
+		groups: l.groups,
 	}
 
 	copy(newLogger.attrs, l.attrs)
-	copy(newLogger.attrs[len(l.attrs):], attrs)
-	copy(newLogger.groups, l.groups)
+	copy(newLogger.paths, l.paths)
+	for _, attr := range attrs {
+		newLogger.attrs = append(newLogger.attrs, attr)
+		newLogger.paths = append(newLogger.paths, l.groups)
+	}
 
 	return newLogger
 }
 
 // WithGroup implements slog.Handler.
 func (l *MockLogger) WithGroup(name string) slog.Handler {
-	l.mu.Lock()
-	defer l.mu.Unlock()
+	if name == "" {
+		return l
+	}
 
-	// Create a new logger that shares the logs slice
+	// Create a new logger that shares the recorder
 	newLogger := &MockLogger{
-		logs:   l.logs,
-		attrs:  make([]slog.Attr, len(l.attrs)),
+		rec:    l.recorder(),
+		attrs:  l.attrs,
+		paths:  l.paths,
 		groups: make([]string, len(l.groups)+1),
 	}
 
-	copy(newLogger.attrs, l.attrs)
 	copy(newLogger.groups, l.groups)
 	newLogger.groups[len(l.groups)] = name
 
@@ -102,22 +138,24 @@ func (l *MockLogger) WithGroup(name string) slog.Handler {
 
 // GetLogs returns all log entries
 func (l *MockLogger) GetLogs() []LogEntry {
-	l.mu.Lock()
-	defer l.mu.Unlock()
+	rec := l.recorder()
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
 
-	logs := make([]LogEntry, len(l.logs))
-	copy(logs, l.logs)
+	logs := make([]LogEntry, len(rec.logs))
+	copy(logs, rec.logs)
 
 	return logs
 }
 
 // GetLogsByLevel returns log entries filtered by level
 func (l *MockLogger) GetLogsByLevel(level slog.Level) []LogEntry {
-	l.mu.Lock()
-	defer l.mu.Unlock()
+	rec := l.recorder()
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
 
 	logs := make([]LogEntry, 0)
-	for _, log := range l.logs {
+	for _, log := range rec.logs {
 		if log.Level == level {
 			logs = append(logs, log)
 		}
@@ -128,21 +166,126 @@ func (l *MockLogger) GetLogsByLevel(level slog.Level) []LogEntry {
 
 // Clear clears all log entries
 func (l *MockLogger) Clear() {
-	l.mu.Lock()
-	defer l.mu.Unlock()
+	rec := l.recorder()
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
 
-	l.logs = make([]LogEntry, 0)
+	rec.logs = make([]LogEntry, 0)
 }
 
 // String returns a string representation of the log entries
 func (l *MockLogger) String() string {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
 	result := ""
-	for _, log := range l.logs {
+	for _, log := range l.GetLogs() {
 		result += fmt.Sprintf("[%s] %s %v\n", log.Level, log.Message, log.Attrs)
 	}
 
 	return result
 }
+
+// Attr returns the resolved value of the attribute at the dotted path, such
+// as "req.id" for the attribute id in the group req. Keys containing dots
+// are matched whole, and the last attribute with a key takes precedence.
+func (e LogEntry) Attr(path string) (slog.Value, bool) {
+	return lookupAttr(e.nestedAttrs(), path)
+}
+
+// AttrsByPath returns the resolved values of the attributes by dotted path,
+// with the groups flattened
+func (e LogEntry) AttrsByPath() map[string]slog.Value {
+	values := make(map[string]slog.Value, len(e.Attrs))
+	flattenAttrs(values, "", e.nestedAttrs())
+	return values
+}
+
+// lookupAttr finds the attribute at path in attrs, descending into groups
+func lookupAttr(attrs []slog.Attr, path string) (slog.Value, bool) {
+	for i := len(attrs) - 1; i >= 0; i-- {
+		a := attrs[i]
+		v := a.Value.Resolve()
+		if a.Key == path {
+			return v, true
+		}
+		if v.Kind() == slog.KindGroup {
+			rest, ok := strings.CutPrefix(path, a.Key+".")
+			if a.Key == "" {
+				rest, ok = path, true
+			}
+			if !ok {
+				continue
+			}
+			if found, ok := lookupAttr(v.Group(), rest); ok {
+				return found, true
+			}
+		}
+	}
+	return slog.Value{}, false
+}
+
+// flattenAttrs adds attrs to values by dotted path, prefixed with prefix
+func flattenAttrs(values map[string]slog.Value, prefix string, attrs []slog.Attr) {
+	for _, a := range attrs {
+		v := a.Value.Resolve()
+		if v.Kind() == slog.KindGroup {
+			p := prefix
+			if a.Key != "" {
+				p += a.Key + "."
+			}
+			flattenAttrs(values, p, v.Group())
+			continue
+		}
+		values[prefix+a.Key] = v
+	}
+}
+
+// recordSource resolves the source of a record from its program counter
+func recordSource(pc uintptr) *slog.Source {
+	if pc == 0 {
+		return nil
+	}
+	f, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return &slog.Source{Function: f.Function, File: f.File, Line: f.Line}
+}
+
+// recorder returns the shared recorder, creating it for a zero MockLogger
+func (l *MockLogger) recorder() *mockRecorder {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rec == nil {
+		l.rec = &mockRecorder{}
+	}
+	return l.rec
+}
+
+// nestedAttrs returns the attributes of e nested in their groups, as a
+// handler would output them
+func (e LogEntry) nestedAttrs() []slog.Attr {
+	if len(e.Groups) != len(e.Attrs) {
+		return e.Attrs
+	}
+	return nestAttrs(e.Attrs, e.Groups)
+}
+
+// nestAttrs nests attrs in the groups of paths. Paths only grow along attrs,
+// so the attributes of a group are the last ones.
+func nestAttrs(attrs []slog.Attr, paths [][]string) []slog.Attr {
+	for i, path := range paths {
+		if len(path) == 0 {
+			continue
+		}
+
+		inner := make([][]string, len(paths)-i)
+		for j := range inner {
+			inner[j] = paths[i+j][1:]
+		}
+
+		nested := make([]slog.Attr, i, i+1)
+		copy(nested, attrs[:i])
+		return append(nested, slog.Attr{
+			Key:   path[0],
+			Value: slog.GroupValue(nestAttrs(attrs[i:], inner)...),
+		})
+	}
+	return attrs
+}
@@ -0,0 +1,302 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// TestingT is the subset of testing.TB used by the MockLogger assertions.
+// It is satisfied by *testing.T, *testing.B and testify's assert.TestingT.
+type TestingT interface {
+	Errorf(format string, args ...any)
+}
+
+// tHelper is implemented by testing.TB to mark helper functions
+type tHelper interface {
+	Helper()
+}
+
+// LogMatch describes the log entries an assertion looks for
+type LogMatch struct {
+	// Level is the level of the entries, unless AnyLevel is set
+	Level    slog.Level
+	AnyLevel bool
+
+	// Message is the exact message of the entries, unless AnyMessage is set
+	Message    string
+	AnyMessage bool
+
+	// Pattern is a regular expression the message must match, overriding
+	// Message and AnyMessage
+	Pattern *regexp.Regexp
+
+	// Attrs must all be present in the entries, which may have other attributes.
+	// Group values match groups containing at least their attributes.
+	Attrs []slog.Attr
+}
+
+// Match returns a LogMatch for entries with level, message msg and attrs
+func Match(level slog.Level, msg string, attrs ...slog.Attr) LogMatch {
+	return LogMatch{Level: level, Message: msg, Attrs: attrs}
+}
+
+// MatchRegexp returns a LogMatch for entries with level, a message matching
+// pattern and attrs. It panics if pattern does not compile.
+func MatchRegexp(level slog.Level, pattern string, attrs ...slog.Attr) LogMatch {
+	return LogMatch{Level: level, Pattern: regexp.MustCompile(pattern), Attrs: attrs}
+}
+
+// Matches reports whether e is matched by m
+func (m LogMatch) Matches(e LogEntry) bool {
+	return m.matchesHeader(e) && len(missingAttrs(m.Attrs, e.nestedAttrs())) == 0
+}
+
+// String returns a representation of m used in failure messages
+func (m LogMatch) String() string {
+	var b strings.Builder
+	if m.AnyLevel {
+		b.WriteString("ANY")
+	} else {
+		b.WriteString(m.Level.String())
+	}
+	switch {
+	case m.Pattern != nil:
+		fmt.Fprintf(&b, " /%s/", m.Pattern)
+	case !m.AnyMessage:
+		fmt.Fprintf(&b, " %q", m.Message)
+	}
+	if len(m.Attrs) > 0 {
+		fmt.Fprintf(&b, " %s", formatAttrs(m.Attrs))
+	}
+	return b.String()
+}
+
+// matchesHeader reports whether the level and message of e are matched by m
+func (m LogMatch) matchesHeader(e LogEntry) bool {
+	if !m.AnyLevel && e.Level != m.Level {
+		return false
+	}
+	if m.Pattern != nil {
+		return m.Pattern.MatchString(e.Message)
+	}
+	return m.AnyMessage || e.Message == m.Message
+}
+
+// GetMockLogger returns the MockLogger handling l, or nil if l was not
+// created by NewMockLogger
+func GetMockLogger(l *slog.Logger) *MockLogger {
+	mock, _ := l.Handler().(*MockLogger)
+	return mock
+}
+
+// Find returns the log entries matched by m
+func (l *MockLogger) Find(m LogMatch) []LogEntry {
+	var found []LogEntry
+	for _, e := range l.GetLogs() {
+		if m.Matches(e) {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// AssertLogged asserts that an entry with level, message msg and attrs was logged
+func (l *MockLogger) AssertLogged(t TestingT, level slog.Level, msg string, attrs ...slog.Attr) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	return l.AssertMatch(t, Match(level, msg, attrs...))
+}
+
+// AssertNotLogged asserts that no entry with level, message msg and attrs was logged
+func (l *MockLogger) AssertNotLogged(t TestingT, level slog.Level, msg string, attrs ...slog.Attr) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	return l.AssertNoMatch(t, Match(level, msg, attrs...))
+}
+
+// AssertCount asserts that want entries were logged at level, with any message
+func (l *MockLogger) AssertCount(t TestingT, level slog.Level, want int) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	return l.AssertMatchCount(t, LogMatch{Level: level, AnyMessage: true}, want)
+}
+
+// AssertMatch asserts that an entry matched by m was logged
+func (l *MockLogger) AssertMatch(t TestingT, m LogMatch) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	logs := l.GetLogs()
+	for _, e := range logs {
+		if m.Matches(e) {
+			return true
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "no log entry matches %s\n", m)
+	for i, e := range logs {
+		if !m.matchesHeader(e) {
+			continue
+		}
+		for _, diff := range missingAttrs(m.Attrs, e.nestedAttrs()) {
+			fmt.Fprintf(&b, "entry %d: %s\n", i, diff)
+		}
+	}
+	writeEntries(&b, logs)
+	t.Errorf("%s", b.String())
+	return false
+}
+
+// AssertNoMatch asserts that no entry matched by m was logged
+func (l *MockLogger) AssertNoMatch(t TestingT, m LogMatch) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	logs := l.GetLogs()
+	var matched []int
+	for i, e := range logs {
+		if m.Matches(e) {
+			matched = append(matched, i)
+		}
+	}
+	if len(matched) == 0 {
+		return true
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "unexpected log entries %v match %s\n", matched, m)
+	writeEntries(&b, logs)
+	t.Errorf("%s", b.String())
+	return false
+}
+
+// AssertMatchCount asserts that want entries matched by m were logged
+func (l *MockLogger) AssertMatchCount(t TestingT, m LogMatch, want int) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	logs := l.GetLogs()
+	got := 0
+	for _, e := range logs {
+		if m.Matches(e) {
+			got++
+		}
+	}
+	if got == want {
+		return true
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "expected %d log entries matching %s, got %d\n", want, m, got)
+	writeEntries(&b, logs)
+	t.Errorf("%s", b.String())
+	return false
+}
+
+// AssertSequence asserts that entries matched by ms were logged in order.
+// Other entries may be logged before, between and after them.
+func (l *MockLogger) AssertSequence(t TestingT, ms ...LogMatch) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	logs := l.GetLogs()
+	next := 0
+	for _, e := range logs {
+		if next < len(ms) && ms[next].Matches(e) {
+			next++
+		}
+	}
+	if next == len(ms) {
+		return true
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "log sequence not found: matched %d of %d entries\n", next, len(ms))
+	for i, m := range ms {
+		mark := " "
+		if i == next {
+			mark = ">"
+		}
+		fmt.Fprintf(&b, "%s %d: %s\n", mark, i, m)
+	}
+	writeEntries(&b, logs)
+	t.Errorf("%s", b.String())
+	return false
+}
+
+// missingAttrs describes the attributes of want that are not in got
+func missingAttrs(want, got []slog.Attr) []string {
+	var diffs []string
+	for _, w := range want {
+		g, ok := findAttr(got, w.Key)
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("missing %s", w.Key))
+			continue
+		}
+
+		wv, gv := w.Value.Resolve(), g.Value.Resolve()
+		if wv.Kind() == slog.KindGroup && gv.Kind() == slog.KindGroup {
+			for _, diff := range missingAttrs(wv.Group(), gv.Group()) {
+				diffs = append(diffs, w.Key+"."+diff)
+			}
+			continue
+		}
+		if !valuesEqual(wv, gv) {
+			diffs = append(diffs, fmt.Sprintf("%s: want %v, got %v", w.Key, wv, gv))
+		}
+	}
+	return diffs
+}
+
+// findAttr returns the last attribute of attrs with key, as it takes precedence
+func findAttr(attrs []slog.Attr, key string) (slog.Attr, bool) {
+	for i := len(attrs) - 1; i >= 0; i-- {
+		if attrs[i].Key == key {
+			return attrs[i], true
+		}
+	}
+	return slog.Attr{}, false
+}
+
+// valuesEqual compares resolved values, comparing KindAny values deeply
+func valuesEqual(a, b slog.Value) bool {
+	if a.Kind() == slog.KindAny && b.Kind() == slog.KindAny {
+		return reflect.DeepEqual(a.Any(), b.Any())
+	}
+	if a.Kind() != b.Kind() {
+		return false
+	}
+	return a.Equal(b)
+}
+
+// writeEntries lists the captured entries for failure messages
+func writeEntries(b *strings.Builder, logs []LogEntry) {
+	if len(logs) == 0 {
+		b.WriteString("no entries captured")
+		return
+	}
+	b.WriteString("captured entries:")
+	for i, e := range logs {
+		fmt.Fprintf(b, "\n  %d: %s %q %s", i, e.Level, e.Message, formatAttrs(e.Attrs))
+	}
+}
+
+// formatAttrs formats attributes as {key=value ...}
+func formatAttrs(attrs []slog.Attr) string {
+	parts := make([]string, len(attrs))
+	for i, a := range attrs {
+		parts[i] = a.String()
+	}
+	return "{" + strings.Join(parts, " ") + "}"
+}
@@ -0,0 +1,142 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingT records the failures of assertions
+type recordingT struct {
+	errors []string
+}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func newAssertMock() (*slog.Logger, *MockLogger) {
+	log := NewMockLogger()
+	log.Info("server started", "port", 8080, slog.Group("tls", "enabled", true, "version", "1.3"))
+	log.Warn("slow request", "path", "/users", "ms", 1200)
+	log.Error("request failed", "path", "/orders", "status", 500)
+	log.Info("server stopped", "reason", "signal")
+	return log, GetMockLogger(log)
+}
+
+func TestAssertLogged(t *testing.T) {
+	_, mock := newAssertMock()
+
+	assert.True(t, mock.AssertLogged(t, slog.LevelInfo, "server started"))
+	assert.True(t, mock.AssertLogged(t, slog.LevelInfo, "server started", slog.Int("port", 8080)))
+	assert.True(t, mock.AssertLogged(t, slog.LevelInfo, "server started", slog.Group("tls", "version", "1.3")))
+	assert.True(t, mock.AssertMatch(t, LogMatch{Level: slog.LevelError, AnyMessage: true, Attrs: []slog.Attr{slog.String("path", "/orders")}}))
+
+	rt := &recordingT{}
+	assert.False(t, mock.AssertLogged(rt, slog.LevelInfo, "server started", slog.Int("port", 9090)))
+	require.Len(t, rt.errors, 1)
+	assert.Contains(t, rt.errors[0], `no log entry matches INFO "server started" {port=9090}`)
+	assert.Contains(t, rt.errors[0], "entry 0: port: want 9090, got 8080")
+	assert.Contains(t, rt.errors[0], `3: INFO "server stopped" {reason=signal}`)
+
+	rt = &recordingT{}
+	assert.False(t, mock.AssertLogged(rt, slog.LevelInfo, "server started", slog.Group("tls", "cipher", "aes")))
+	require.Len(t, rt.errors, 1)
+	assert.Contains(t, rt.errors[0], "entry 0: tls.missing cipher")
+
+	rt = &recordingT{}
+	assert.False(t, mock.AssertLogged(rt, slog.LevelDebug, "server started"))
+	assert.Len(t, rt.errors, 1)
+}
+
+func TestAssertNotLogged(t *testing.T) {
+	_, mock := newAssertMock()
+
+	assert.True(t, mock.AssertNotLogged(t, slog.LevelError, "server started"))
+	assert.True(t, mock.AssertNotLogged(t, slog.LevelError, "request failed", slog.Int("status", 404)))
+
+	rt := &recordingT{}
+	assert.False(t, mock.AssertNoMatch(rt, LogMatch{Level: slog.LevelInfo, AnyMessage: true}))
+	require.Len(t, rt.errors, 1)
+	assert.Contains(t, rt.errors[0], "unexpected log entries [0 3] match INFO")
+}
+
+func TestAssertEmptyMessage(t *testing.T) {
+	log, mock := newAssertMock()
+
+	// An empty message is matched literally
+	assert.True(t, mock.AssertNotLogged(t, slog.LevelInfo, ""))
+	log.Info("", "id", 1)
+	assert.True(t, mock.AssertLogged(t, slog.LevelInfo, "", slog.Int("id", 1)))
+	assert.Len(t, mock.Find(Match(slog.LevelInfo, "")), 1)
+	assert.Equal(t, `INFO ""`, Match(slog.LevelInfo, "").String())
+	assert.Equal(t, "INFO", LogMatch{Level: slog.LevelInfo, AnyMessage: true}.String())
+}
+
+func TestAssertCount(t *testing.T) {
+	_, mock := newAssertMock()
+
+	assert.True(t, mock.AssertCount(t, slog.LevelInfo, 2))
+	assert.True(t, mock.AssertCount(t, slog.LevelDebug, 0))
+	assert.True(t, mock.AssertMatchCount(t, LogMatch{AnyLevel: true, AnyMessage: true, Attrs: []slog.Attr{slog.Any("path", nil)}}, 0))
+	assert.True(t, mock.AssertMatchCount(t, MatchRegexp(slog.LevelInfo, "^server "), 2))
+
+	rt := &recordingT{}
+	assert.False(t, mock.AssertCount(rt, slog.LevelWarn, 2))
+	require.Len(t, rt.errors, 1)
+	assert.Contains(t, rt.errors[0], "expected 2 log entries matching WARN, got 1")
+}
+
+func TestAssertMatchRegexp(t *testing.T) {
+	_, mock := newAssertMock()
+
+	assert.True(t, mock.AssertMatch(t, MatchRegexp(slog.LevelWarn, `^slow`, slog.Int("ms", 1200))))
+	assert.True(t, mock.AssertMatch(t, LogMatch{AnyLevel: true, Message: "request failed"}))
+	assert.Len(t, mock.Find(MatchRegexp(slog.LevelInfo, `stop|start`)), 2)
+
+	rt := &recordingT{}
+	assert.False(t, mock.AssertMatch(rt, MatchRegexp(slog.LevelWarn, `^fast`)))
+	require.Len(t, rt.errors, 1)
+	assert.Contains(t, rt.errors[0], "no log entry matches WARN /^fast/")
+
+	assert.Panics(t, func() { MatchRegexp(slog.LevelInfo, "(") })
+}
+
+func TestAssertSequence(t *testing.T) {
+	_, mock := newAssertMock()
+
+	assert.True(t, mock.AssertSequence(t,
+		Match(slog.LevelInfo, "server started"),
+		Match(slog.LevelError, "request failed"),
+		Match(slog.LevelInfo, "server stopped"),
+	))
+
+	rt := &recordingT{}
+	assert.False(t, mock.AssertSequence(rt,
+		Match(slog.LevelInfo, "server started"),
+		Match(slog.LevelInfo, "server stopped"),
+		Match(slog.LevelWarn, "slow request"),
+	))
+	require.Len(t, rt.errors, 1)
+	assert.Contains(t, rt.errors[0], "matched 2 of 3 entries")
+	assert.Contains(t, rt.errors[0], `> 2: WARN "slow request"`)
+}
+
+func TestAssertEmpty(t *testing.T) {
+	mock := &MockLogger{}
+
+	assert.True(t, mock.AssertCount(t, slog.LevelInfo, 0))
+
+	rt := &recordingT{}
+	assert.False(t, mock.AssertLogged(rt, slog.LevelInfo, "anything"))
+	require.Len(t, rt.errors, 1)
+	assert.Contains(t, rt.errors[0], "no entries captured")
+}
+
+func TestGetMockLogger(t *testing.T) {
+	assert.NotNil(t, GetMockLogger(NewMockLogger()))
+	assert.Nil(t, GetMockLogger(slog.Default()))
+}
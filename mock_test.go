@@ -0,0 +1,158 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockLoggerDerived(t *testing.T) {
+	log := NewMockLogger()
+	mock := GetMockLogger(log)
+
+	log.Info("root")
+	log.With("service", "api").Info("with attrs")
+	log.WithGroup("req").With("id", "r1").Info("with group", "status", 200)
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 3)
+	assert.Equal(t, "root", logs[0].Message)
+	assert.Equal(t, []slog.Attr{slog.String("service", "api")}, logs[1].Attrs)
+	assert.Equal(t, [][]string{nil}, logs[1].Groups)
+	assert.Equal(t, []slog.Attr{slog.String("id", "r1"), slog.Int("status", 200)}, logs[2].Attrs)
+	assert.Equal(t, [][]string{{"req"}, {"req"}}, logs[2].Groups)
+
+	mock.AssertLogged(t, slog.LevelInfo, "with group", slog.Group("req", "id", "r1", "status", 200))
+	mock.AssertNotLogged(t, slog.LevelInfo, "with group", slog.String("id", "r1"))
+
+	mock.Clear()
+	log.With("a", 1).Info("after clear")
+	assert.Len(t, mock.GetLogs(), 1)
+}
+
+func TestMockLoggerGroupPaths(t *testing.T) {
+	log := NewMockLogger()
+	log.With("a", 1).WithGroup("g1").With("b", 2).WithGroup("g2").Info("nested", "c", 3)
+
+	logs := GetMockLogger(log).GetLogs()
+	require.Len(t, logs, 1)
+	assert.Equal(t, [][]string{nil, {"g1"}, {"g1", "g2"}}, logs[0].Groups)
+	assert.Equal(t, []slog.Attr{
+		slog.Int("a", 1),
+		slog.Group("g1", slog.Int("b", 2), slog.Group("g2", slog.Int("c", 3))),
+	}, logs[0].nestedAttrs())
+	assert.Equal(t, "[INFO] nested [a=1 b=2 c=3]\n", GetMockLogger(log).String())
+}
+
+func TestMockLoggerZeroValue(t *testing.T) {
+	mock := &MockLogger{}
+	log := slog.New(mock)
+
+	log.WithGroup("g").Info("child", "k", "v")
+	log.Info("parent")
+
+	assert.Len(t, mock.GetLogs(), 2)
+}
+
+func TestMockLoggerConcurrent(t *testing.T) {
+	log := NewMockLogger()
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			child := log.With("worker", i).WithGroup("job")
+			for j := range 10 {
+				child.Info("done", "n", j)
+			}
+		}()
+	}
+	wg.Wait()
+
+	mock := GetMockLogger(log)
+	mock.AssertCount(t, slog.LevelInfo, 100)
+	mock.AssertMatchCount(t, Match(slog.LevelInfo, "done", slog.Int("worker", 3)), 10)
+}
+
+// contextKey is a context key for tests
+type contextKey string
+
+func TestMockLoggerMetadata(t *testing.T) {
+	log := NewMockLogger()
+	ctx := ContextWithAttrs(context.WithValue(context.Background(), contextKey("user"), "alice"),
+		slog.String("request_id", "r1"))
+
+	before := time.Now()
+	_, file, line, _ := runtime.Caller(0)
+	log.InfoContext(ctx, "with metadata")
+
+	logs := GetMockLogger(log).GetLogs()
+	require.Len(t, logs, 1)
+	entry := logs[0]
+
+	assert.False(t, entry.Time.Before(before))
+	require.NotNil(t, entry.Source)
+	assert.Equal(t, file, entry.Source.File)
+	assert.Equal(t, line+1, entry.Source.Line)
+	assert.Contains(t, entry.Source.Function, "TestMockLoggerMetadata")
+
+	assert.Equal(t, "alice", entry.Context.Value(contextKey("user")))
+	assert.Equal(t, []slog.Attr{slog.String("request_id", "r1")}, entry.ContextAttrs)
+}
+
+func TestMockLoggerNoSource(t *testing.T) {
+	mock := &MockLogger{}
+	require.NoError(t, mock.Handle(context.Background(), slog.NewRecord(time.Time{}, slog.LevelInfo, "no pc", 0)))
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 1)
+	assert.Nil(t, logs[0].Source)
+}
+
+func TestLogEntryAttr(t *testing.T) {
+	log := NewMockLogger()
+	log.With("service", "api", "dotted.key", "d").
+		WithGroup("req").
+		Info("lookup", "id", "r1", slog.Group("user", "name", "alice"), slog.Group("", "inline", true))
+
+	entry := GetMockLogger(log).GetLogs()[0]
+
+	tests := []struct {
+		path string
+		want slog.Value
+	}{
+		{"service", slog.StringValue("api")},
+		{"dotted.key", slog.StringValue("d")},
+		{"req.id", slog.StringValue("r1")},
+		{"req.user.name", slog.StringValue("alice")},
+		{"req.inline", slog.BoolValue(true)},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			v, ok := entry.Attr(test.path)
+			require.True(t, ok)
+			assert.True(t, test.want.Equal(v), "got %v", v)
+		})
+	}
+
+	for _, path := range []string{"id", "req.name", "req.user.age", "missing"} {
+		_, ok := entry.Attr(path)
+		assert.False(t, ok, path)
+	}
+
+	v, ok := entry.Attr("req.user")
+	require.True(t, ok)
+	assert.Equal(t, slog.KindGroup, v.Kind())
+
+	byPath := entry.AttrsByPath()
+	assert.Len(t, byPath, 5)
+	assert.Equal(t, "alice", byPath["req.user.name"].String())
+	assert.True(t, byPath["req.inline"].Bool())
+}
@@ -0,0 +1,39 @@
+// Package otel correlates log records with OpenTelemetry traces.
+//
+// Importing the package registers an extractor that reads the span context
+// from the context passed to the *Context logging methods, so a logger created
+// with Config.EnableTracing adds trace_id, span_id and trace_flags:
+//
+//	import _ "github.com/legrch/logger/otel"
+package otel
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/legrch/logger"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func init() {
+	logger.RegisterTraceExtractor(Extract)
+}
+
+// Extract returns the trace context of the span carried by ctx
+func Extract(ctx context.Context) (logger.TraceContext, bool) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return logger.TraceContext{}, false
+	}
+
+	return logger.TraceContext{
+		TraceID:    sc.TraceID(),
+		SpanID:     sc.SpanID(),
+		TraceFlags: byte(sc.TraceFlags()),
+	}, true
+}
+
+// NewHandler wraps next with a handler that adds the OpenTelemetry trace context
+func NewHandler(next slog.Handler) slog.Handler {
+	return logger.NewTraceHandler(next, Extract)
+}
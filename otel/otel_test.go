@@ -0,0 +1,58 @@
+package otel
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/legrch/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func spanContext(t *testing.T) context.Context {
+	t.Helper()
+
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+	return trace.ContextWithSpanContext(context.Background(), sc)
+}
+
+func TestExtract(t *testing.T) {
+	tc, ok := Extract(spanContext(t))
+	require.True(t, ok)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", tc.Traceparent())
+
+	_, ok = Extract(context.Background())
+	assert.False(t, ok)
+}
+
+func TestNewHandler(t *testing.T) {
+	log := logger.NewMockLogger()
+	mock := log.Handler().(*logger.MockLogger)
+
+	slog.New(NewHandler(mock)).InfoContext(spanContext(t), "traced")
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 1)
+	assert.Equal(t, []slog.Attr{
+		slog.String(logger.TraceIDKey, "4bf92f3577b34da6a3ce929d0e0e4736"),
+		slog.String(logger.SpanIDKey, "00f067aa0ba902b7"),
+		slog.String(logger.TraceFlagsKey, "01"),
+	}, logs[0].Attrs)
+}
+
+func TestRegisteredExtractor(t *testing.T) {
+	tc, ok := logger.ExtractTraceContext(spanContext(t))
+	require.True(t, ok)
+	assert.Equal(t, "00f067aa0ba902b7", tc.SpanIDString())
+}
@@ -0,0 +1,278 @@
+// Package otellog provides a slog.Handler that emits records as OpenTelemetry
+// log records through a LoggerProvider.
+//
+// Importing the package registers the "otel" sink, which uses the global
+// LoggerProvider:
+//
+//	import _ "github.com/legrch/logger/otellog"
+//
+//	cfg.Sinks = []string{"stdout", otellog.SinkName}
+package otellog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"runtime"
+	"time"
+
+	"github.com/legrch/logger"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+)
+
+// SinkName is the name the handler is registered under as a logger sink
+const SinkName = "otel"
+
+// DefaultScope is the instrumentation scope name used when none is given
+const DefaultScope = "github.com/legrch/logger"
+
+// Source code attribute keys
+const (
+	CodeFilePathKey     = "code.file.path"
+	CodeLineNumberKey   = "code.line.number"
+	CodeFunctionNameKey = "code.function.name"
+)
+
+func init() {
+	logger.RegisterSink(SinkName, func(_ *logger.Config, opts *slog.HandlerOptions) (slog.Handler, error) {
+		return NewHandler(&Options{
+			Level:     opts.Level,
+			AddSource: opts.AddSource,
+		}), nil
+	})
+}
+
+// Options configures a Handler
+type Options struct {
+	// LoggerProvider provides the OTel logger, defaults to the global provider
+	LoggerProvider log.LoggerProvider
+
+	// Scope is the instrumentation scope name, defaults to DefaultScope
+	Scope string
+
+	// Version is the instrumentation scope version
+	Version string
+
+	// Level is the minimum enabled logging level
+	Level slog.Leveler
+
+	// AddSource adds the code.* source attributes
+	AddSource bool
+}
+
+// Handler is a slog.Handler that converts records to OpenTelemetry log records.
+type Handler struct {
+	provider  log.LoggerProvider
+	logger    log.Logger
+	level     slog.Leveler
+	addSource bool
+	goas      []groupOrAttrs
+}
+
+// groupOrAttrs holds either a group name or attributes added with WithAttrs
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+// NewHandler creates a new Handler.
+func NewHandler(opts *Options) *Handler {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	provider := opts.LoggerProvider
+	if provider == nil {
+		provider = global.GetLoggerProvider()
+	}
+
+	scope := opts.Scope
+	if scope == "" {
+		scope = DefaultScope
+	}
+
+	var loggerOpts []log.LoggerOption
+	if opts.Version != "" {
+		loggerOpts = append(loggerOpts, log.WithInstrumentationVersion(opts.Version))
+	}
+
+	return &Handler{
+		provider:  provider,
+		logger:    provider.Logger(scope, loggerOpts...),
+		level:     opts.Level,
+		addSource: opts.AddSource,
+	}
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.level != nil && level < h.level.Level() {
+		return false
+	}
+	return h.logger.Enabled(ctx, log.EnabledParameters{Severity: Severity(level)})
+}
+
+// Handle implements slog.Handler.
+//
+//nolint:gocritic // Cannot change signature due to interface contract
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	var record log.Record
+	record.SetTimestamp(r.Time)
+	record.SetObservedTimestamp(time.Now())
+	record.SetSeverity(Severity(r.Level))
+	record.SetSeverityText(r.Level.String())
+	record.SetBody(log.StringValue(r.Message))
+
+	// Convert record attributes and nest them in the handler groups
+	kvs := make([]log.KeyValue, 0, r.NumAttrs())
+	r.Attrs(func(attr slog.Attr) bool {
+		kvs = appendAttr(kvs, attr)
+		return true
+	})
+
+	for i := len(h.goas) - 1; i >= 0; i-- {
+		goa := h.goas[i]
+		if goa.group != "" {
+			if len(kvs) > 0 {
+				kvs = []log.KeyValue{log.Map(goa.group, kvs...)}
+			}
+			continue
+		}
+
+		converted := make([]log.KeyValue, 0, len(goa.attrs)+len(kvs))
+		for _, attr := range goa.attrs {
+			converted = appendAttr(converted, attr)
+		}
+		kvs = append(converted, kvs...)
+	}
+
+	if h.addSource && r.PC != 0 {
+		fs := runtime.CallersFrames([]uintptr{r.PC})
+		f, _ := fs.Next()
+		if f.File != "" {
+			kvs = append(kvs,
+				log.String(CodeFilePathKey, f.File),
+				log.Int(CodeLineNumberKey, f.Line),
+				log.String(CodeFunctionNameKey, f.Function),
+			)
+		}
+	}
+
+	record.AddAttributes(kvs...)
+	h.logger.Emit(ctx, record)
+
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(groupOrAttrs{attrs: attrs})
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(groupOrAttrs{group: name})
+}
+
+// withGroupOrAttrs returns a copy of the handler with goa appended
+func (h *Handler) withGroupOrAttrs(goa groupOrAttrs) *Handler {
+	h2 := *h
+	h2.goas = make([]groupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h.goas)] = goa
+	return &h2
+}
+
+// Flush forces the LoggerProvider to export buffered records, if it supports it.
+// The provider is owned by the application, so the handler never shuts it down.
+func (h *Handler) Flush(ctx context.Context) error {
+	if f, ok := h.provider.(interface{ ForceFlush(context.Context) error }); ok {
+		return f.ForceFlush(ctx)
+	}
+	return nil
+}
+
+// Severity converts a slog level to an OpenTelemetry severity number.
+// slog levels are 4 apart and map onto the base severity of each OTel range,
+// so LevelDebug is DEBUG, LevelInfo is INFO and LevelError is ERROR.
+func Severity(level slog.Level) log.Severity {
+	sev := int(level) + int(log.SeverityInfo)
+	switch {
+	case sev < int(log.SeverityTrace1):
+		return log.SeverityTrace1
+	case sev > int(log.SeverityFatal4):
+		return log.SeverityFatal4
+	default:
+		return log.Severity(sev)
+	}
+}
+
+// appendAttr converts attr and appends it to kvs, inlining empty-key groups
+func appendAttr(kvs []log.KeyValue, attr slog.Attr) []log.KeyValue {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return kvs
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		group := attr.Value.Group()
+		if len(group) == 0 {
+			return kvs
+		}
+
+		members := make([]log.KeyValue, 0, len(group))
+		for _, member := range group {
+			members = appendAttr(members, member)
+		}
+
+		if attr.Key == "" {
+			return append(kvs, members...)
+		}
+		return append(kvs, log.Map(attr.Key, members...))
+	}
+
+	return append(kvs, log.KeyValue{Key: attr.Key, Value: convertValue(attr.Value)})
+}
+
+// convertValue converts a resolved, non-group slog value to an OTel log value
+func convertValue(v slog.Value) log.Value {
+	switch v.Kind() {
+	case slog.KindString:
+		return log.StringValue(v.String())
+	case slog.KindInt64:
+		return log.Int64Value(v.Int64())
+	case slog.KindUint64:
+		u := v.Uint64()
+		if u > math.MaxInt64 {
+			return log.StringValue(fmt.Sprint(u))
+		}
+		return log.Int64Value(int64(u))
+	case slog.KindFloat64:
+		return log.Float64Value(v.Float64())
+	case slog.KindBool:
+		return log.BoolValue(v.Bool())
+	case slog.KindDuration:
+		return log.Int64Value(v.Duration().Nanoseconds())
+	case slog.KindTime:
+		return log.StringValue(v.Time().Format(time.RFC3339Nano))
+	default:
+		switch x := v.Any().(type) {
+		case []byte:
+			return log.BytesValue(x)
+		case error:
+			return log.StringValue(x.Error())
+		case fmt.Stringer:
+			return log.StringValue(x.String())
+		default:
+			return log.StringValue(fmt.Sprintf("%+v", x))
+		}
+	}
+}
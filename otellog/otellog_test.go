@@ -0,0 +1,161 @@
+package otellog
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/legrch/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+// memoryExporter is an in-memory sdklog.Exporter
+type memoryExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *memoryExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (*memoryExporter) Shutdown(context.Context) error   { return nil }
+func (*memoryExporter) ForceFlush(context.Context) error { return nil }
+
+func (e *memoryExporter) Records() []sdklog.Record {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]sdklog.Record(nil), e.records...)
+}
+
+func newTestHandler(t *testing.T, opts *Options) (*Handler, *memoryExporter) {
+	t.Helper()
+
+	exporter := &memoryExporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.LoggerProvider = provider
+
+	return NewHandler(opts), exporter
+}
+
+func attributes(r *sdklog.Record) map[string]log.Value {
+	m := make(map[string]log.Value)
+	r.WalkAttributes(func(kv log.KeyValue) bool {
+		m[kv.Key] = kv.Value
+		return true
+	})
+	return m
+}
+
+func TestHandler(t *testing.T) {
+	handler, exporter := newTestHandler(t, &Options{AddSource: true})
+	log := slog.New(handler)
+
+	log.Warn("disk almost full", "free", 42, "path", "/var", "ratio", 0.97)
+
+	records := exporter.Records()
+	require.Len(t, records, 1)
+
+	r := records[0]
+	assert.Equal(t, "disk almost full", r.Body().AsString())
+	assert.Equal(t, "WARN", r.SeverityText())
+	assert.EqualValues(t, 13, r.Severity())
+
+	attrs := attributes(&r)
+	assert.Equal(t, int64(42), attrs["free"].AsInt64())
+	assert.Equal(t, "/var", attrs["path"].AsString())
+	assert.InDelta(t, 0.97, attrs["ratio"].AsFloat64(), 0.0001)
+	assert.Contains(t, attrs[CodeFilePathKey].AsString(), "otellog_test.go")
+	assert.Positive(t, attrs[CodeLineNumberKey].AsInt64())
+	assert.Contains(t, attrs[CodeFunctionNameKey].AsString(), "TestHandler")
+}
+
+func TestHandlerGroups(t *testing.T) {
+	handler, exporter := newTestHandler(t, nil)
+	log := slog.New(handler).With("service", "api").WithGroup("http").With("method", "GET")
+
+	log.Info("request", slog.Group("response", "status", 200))
+
+	records := exporter.Records()
+	require.Len(t, records, 1)
+
+	attrs := attributes(&records[0])
+	assert.Equal(t, "api", attrs["service"].AsString())
+
+	httpAttrs := attrs["http"].AsMap()
+	require.Len(t, httpAttrs, 2)
+	assert.Equal(t, "method", httpAttrs[0].Key)
+	assert.Equal(t, "GET", httpAttrs[0].Value.AsString())
+	assert.Equal(t, "response", httpAttrs[1].Key)
+	assert.Equal(t, "status", httpAttrs[1].Value.AsMap()[0].Key)
+	assert.Equal(t, int64(200), httpAttrs[1].Value.AsMap()[0].Value.AsInt64())
+}
+
+func TestHandlerEnabled(t *testing.T) {
+	handler, exporter := newTestHandler(t, &Options{Level: slog.LevelInfo})
+	log := slog.New(handler)
+
+	log.Debug("hidden")
+	log.Info("shown")
+
+	records := exporter.Records()
+	require.Len(t, records, 1)
+	assert.Equal(t, "shown", records[0].Body().AsString())
+}
+
+func TestSeverity(t *testing.T) {
+	tests := []struct {
+		level slog.Level
+		want  log.Severity
+	}{
+		{slog.LevelDebug - 8, log.SeverityTrace1},
+		{slog.LevelDebug, log.SeverityDebug},
+		{slog.LevelInfo, log.SeverityInfo},
+		{slog.LevelInfo + 1, log.SeverityInfo2},
+		{slog.LevelWarn, log.SeverityWarn},
+		{slog.LevelError, log.SeverityError},
+		{slog.LevelError + 4, log.SeverityFatal},
+		{slog.LevelError + 100, log.SeverityFatal4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, Severity(tt.level))
+		})
+	}
+}
+
+func TestSink(t *testing.T) {
+	log, err := logger.New(&logger.Config{
+		Level: "info",
+		Sinks: []string{SinkName},
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, log)
+}
+
+func TestFlush(t *testing.T) {
+	exporter := &memoryExporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	handler := NewHandler(&Options{LoggerProvider: provider})
+	slog.New(handler).Info("batched")
+
+	require.NoError(t, handler.Flush(context.Background()))
+	assert.Len(t, exporter.Records(), 1)
+}
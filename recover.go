@@ -0,0 +1,194 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime/debug"
+	"time"
+)
+
+// Panic attribute keys
+const (
+	PanicKey      = "panic"
+	PanicStackKey = "stack"
+)
+
+// DefaultPanicMsg is the default message of the record logged for a panic
+const DefaultPanicMsg = "panic recovered"
+
+// exitShutdownTimeout bounds the Shutdown before exiting on a fatal error
+const exitShutdownTimeout = 5 * time.Second
+
+// exit terminates the program, replaced in tests
+var exit = os.Exit
+
+// RecoverAction determines what happens after a panic has been logged
+type RecoverAction int
+
+// Recover actions
+const (
+	// RecoverDefault is the zero value, the default action of the caller:
+	// RecoverRepanic for Recover and HandlePanic, and RecoverReturnError
+	// for httplog.Recover
+	RecoverDefault RecoverAction = iota
+
+	// RecoverRepanic panics again with the recovered value
+	RecoverRepanic
+
+	// RecoverReturnError stores a *PanicError in RecoverOptions.Err and returns
+	RecoverReturnError
+
+	// RecoverExit flushes the default logger and exits with RecoverOptions.ExitCode
+	RecoverExit
+)
+
+// RecoverOptions configures Recover
+type RecoverOptions struct {
+	// Logger is the logger the panic is logged to, defaults to Default()
+	Logger *slog.Logger
+
+	// Message is the message of the panic record, defaults to DefaultPanicMsg
+	Message string
+
+	// Action is what happens after the panic is logged, see RecoverDefault
+	Action RecoverAction
+
+	// Err receives the *PanicError with RecoverReturnError, typically the
+	// address of a named error result of the deferring function
+	Err *error
+
+	// ExitCode is the exit code with RecoverExit, defaults to 2 like an unrecovered panic
+	ExitCode int
+}
+
+// PanicError is a recovered panic
+type PanicError struct {
+	// Value is the value passed to panic
+	Value any
+
+	// Stack is the stack of the panicking goroutine
+	Stack []byte
+}
+
+// Error implements error.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Recover recovers a panic, logs its value and goroutine stack with the
+// context attributes at LevelPanic, or LevelFatal when exiting, and then
+// acts as configured. It must be called directly with defer:
+//
+//	func work(ctx context.Context) (err error) {
+//		defer logger.Recover(ctx, &logger.RecoverOptions{Action: logger.RecoverReturnError, Err: &err})
+//		...
+//	}
+func Recover(ctx context.Context, opts *RecoverOptions) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	HandlePanic(ctx, v, opts)
+}
+
+// HandlePanic logs and acts on a value returned by recover, for callers
+// that recover panics themselves
+func HandlePanic(ctx context.Context, v any, opts *RecoverOptions) {
+	if opts == nil {
+		opts = &RecoverOptions{}
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	perr := &PanicError{Value: v, Stack: debug.Stack()}
+
+	log := opts.Logger
+	if log == nil {
+		log = Default()
+	}
+	msg := opts.Message
+	if msg == "" {
+		msg = DefaultPanicMsg
+	}
+	level := LevelPanic
+	if opts.Action == RecoverExit {
+		level = LevelFatal
+	}
+
+	attrs := []slog.Attr{
+		slog.String(PanicKey, fmt.Sprint(v)),
+		slog.String(PanicStackKey, string(perr.Stack)),
+	}
+	if err, ok := v.(error); ok {
+		// The goroutine stack is logged already
+		attrs = append(attrs, slog.Any(ErrorKey, errorValue{err: err}))
+	}
+	log.LogAttrs(ctx, level, msg, attrs...)
+
+	switch opts.Action {
+	case RecoverReturnError:
+		if opts.Err != nil {
+			*opts.Err = perr
+		}
+	case RecoverExit:
+		code := opts.ExitCode
+		if code == 0 {
+			code = 2
+		}
+		shutdownAndExit(ctx, code)
+	default:
+		panic(v)
+	}
+}
+
+// shutdownAndExit flushes the default logger and exits with code
+func shutdownAndExit(ctx context.Context, code int) {
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), exitShutdownTimeout)
+	_ = Shutdown(shutdownCtx)
+	cancel()
+
+	exit(code)
+}
+
+// Go runs fn in a new goroutine, logging a panic before panicking again
+func Go(ctx context.Context, fn func(ctx context.Context)) {
+	GoWithOptions(ctx, nil, fn)
+}
+
+// GoWithOptions runs fn in a new goroutine, handling a panic with Recover
+// and the given options. The returned channel receives the *PanicError with
+// RecoverReturnError and is closed when fn returns.
+func GoWithOptions(ctx context.Context, opts *RecoverOptions, fn func(ctx context.Context)) <-chan error {
+	done := make(chan error, 1)
+
+	go func() {
+		defer close(done)
+
+		var err error
+		defer func() {
+			if err != nil {
+				done <- err
+			}
+		}()
+
+		o := RecoverOptions{}
+		if opts != nil {
+			o = *opts
+		}
+		o.Err = &err
+		defer Recover(ctx, &o)
+
+		fn(ctx)
+	}()
+
+	return done
+}
@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func panicAttrs(t *testing.T, entry LogEntry) map[string]slog.Value {
+	t.Helper()
+
+	attrs := make(map[string]slog.Value, len(entry.Attrs))
+	for _, attr := range entry.Attrs {
+		attrs[attr.Key] = attr.Value
+	}
+	require.Contains(t, attrs, PanicKey)
+	require.Contains(t, attrs, PanicStackKey)
+	return attrs
+}
+
+func TestRecoverRepanic(t *testing.T) {
+	mock := &MockLogger{}
+	log := slog.New(NewContextHandler(mock))
+	ctx := ContextWithAttrs(context.Background(), slog.String("job", "sync"))
+
+	assert.PanicsWithValue(t, "boom", func() {
+		defer Recover(ctx, &RecoverOptions{Logger: log})
+		panic("boom")
+	})
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 1)
+	assert.Equal(t, LevelPanic, logs[0].Level)
+	assert.Equal(t, DefaultPanicMsg, logs[0].Message)
+
+	attrs := panicAttrs(t, logs[0])
+	assert.Equal(t, "boom", attrs[PanicKey].String())
+	assert.Contains(t, attrs[PanicStackKey].String(), "TestRecoverRepanic")
+	assert.Equal(t, "sync", attrs["job"].String())
+}
+
+func TestRecoverReturnError(t *testing.T) {
+	mock := &MockLogger{}
+	cause := errors.New("bad state")
+
+	work := func() (err error) {
+		defer Recover(context.Background(), &RecoverOptions{
+			Logger:  slog.New(mock),
+			Message: "worker panicked",
+			Action:  RecoverReturnError,
+			Err:     &err,
+		})
+		panic(cause)
+	}
+
+	err := work()
+	var perr *PanicError
+	require.ErrorAs(t, err, &perr)
+	assert.Equal(t, cause, perr.Value)
+	assert.ErrorIs(t, err, cause)
+	assert.EqualError(t, err, "panic: bad state")
+	assert.NotEmpty(t, perr.Stack)
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 1)
+	assert.Equal(t, "worker panicked", logs[0].Message)
+	assert.Contains(t, panicAttrs(t, logs[0]), ErrorKey)
+}
+
+func TestRecoverExit(t *testing.T) {
+	var code int
+	prev := exit
+	exit = func(c int) { code = c }
+	t.Cleanup(func() { exit = prev })
+
+	mock := &MockLogger{}
+	func() {
+		defer Recover(context.Background(), &RecoverOptions{Logger: slog.New(mock), Action: RecoverExit})
+		panic("fatal")
+	}()
+
+	assert.Equal(t, 2, code)
+	logs := mock.GetLogs()
+	require.Len(t, logs, 1)
+	assert.Equal(t, LevelFatal, logs[0].Level)
+}
+
+func TestRecoverNoPanic(t *testing.T) {
+	mock := &MockLogger{}
+	func() {
+		defer Recover(context.Background(), &RecoverOptions{Logger: slog.New(mock)})
+	}()
+	assert.Empty(t, mock.GetLogs())
+}
+
+func TestGoWithOptions(t *testing.T) {
+	mock := &MockLogger{}
+
+	done := GoWithOptions(context.Background(), &RecoverOptions{
+		Logger: slog.New(mock),
+		Action: RecoverReturnError,
+	}, func(context.Context) {
+		panic("in goroutine")
+	})
+
+	err, ok := <-done
+	require.True(t, ok)
+	assert.EqualError(t, err, "panic: in goroutine")
+	_, ok = <-done
+	assert.False(t, ok)
+	assert.Len(t, mock.GetLogs(), 1)
+
+	// Without a panic the channel is closed without a value
+	done = GoWithOptions(context.Background(), nil, func(context.Context) {})
+	_, ok = <-done
+	assert.False(t, ok)
+}
+
+func TestReplaceLevelName(t *testing.T) {
+	for level, name := range map[slog.Level]string{
+		LevelTrace:      "TRACE",
+		LevelPanic:      "PANIC",
+		LevelFatal:      "FATAL",
+		slog.LevelError: "ERROR",
+	} {
+		attr := replaceLevelName(nil, slog.Any(slog.LevelKey, level))
+		assert.Equal(t, name, attr.Value.String())
+	}
+
+	// Attributes in groups are left as they are
+	attr := replaceLevelName([]string{"g"}, slog.Any(slog.LevelKey, LevelFatal))
+	assert.Equal(t, LevelFatal, attr.Value.Any())
+}
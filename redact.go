@@ -0,0 +1,460 @@
+package logger
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RedactStrategy determines how sensitive values are replaced
+type RedactStrategy string
+
+// Redaction strategies
+const (
+	// RedactMask replaces the value with the mask
+	RedactMask RedactStrategy = "mask"
+
+	// RedactHash replaces the value with a truncated HMAC-SHA256 keyed with
+	// RedactOptions.HashKey, so equal values can still be correlated
+	RedactHash RedactStrategy = "hash"
+
+	// RedactTruncate keeps the first few characters of the value
+	RedactTruncate RedactStrategy = "truncate"
+)
+
+// DefaultRedactMask is the default replacement for redacted values
+const DefaultRedactMask = "[REDACTED]"
+
+// defaultTruncateKeep is the default number of characters kept by RedactTruncate
+const defaultTruncateKeep = 4
+
+// DefaultRedactKeys are the key rules used when no keys are configured
+var DefaultRedactKeys = []string{
+	"password", "passwd", "secret", "*_secret", "token", "*_token",
+	"api_key", "apikey", "authorization", "cookie", "set-cookie",
+}
+
+// RedactPattern matches sensitive substrings of string values
+type RedactPattern struct {
+	// Name identifies the pattern
+	Name string
+
+	// Regexp matches candidate substrings
+	Regexp *regexp.Regexp
+
+	// Validate optionally confirms a match, such as a checksum
+	Validate func(match string) bool
+}
+
+// RedactOptions configures a RedactHandler
+type RedactOptions struct {
+	// Keys are case-insensitive key rules. A rule without dots is a glob
+	// matched against the attribute key at any depth, a rule with dots is
+	// matched against the full group path, such as "http.headers.authorization".
+	Keys []string
+
+	// Patterns are matched against string values and the message
+	Patterns []RedactPattern
+
+	// Strategy is the replacement strategy, defaults to RedactMask
+	Strategy RedactStrategy
+
+	// Mask is the replacement used by RedactMask, defaults to DefaultRedactMask
+	Mask string
+
+	// TruncateKeep is the number of characters kept by RedactTruncate
+	TruncateKeep int
+
+	// HashKey is the HMAC key used by RedactHash. If it is empty, a random
+	// key is generated, so hashes can only be correlated within the process.
+	HashKey []byte
+}
+
+// DefaultRedactPatterns returns the built-in patterns for credit card numbers
+// (with a known issuer prefix and length, Luhn checked), JWTs, AWS access key
+// IDs and email addresses
+func DefaultRedactPatterns() []RedactPattern {
+	return []RedactPattern{
+		{
+			Name:     "credit_card",
+			Regexp:   regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+			Validate: cardNumberValid,
+		},
+		{
+			Name:   "jwt",
+			Regexp: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+		},
+		{
+			Name:   "aws_access_key",
+			Regexp: regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA)[0-9A-Z]{16}\b`),
+		},
+		{
+			Name:   "email",
+			Regexp: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+		},
+	}
+}
+
+// cardNumberValid reports whether s has the prefix and length of a Visa,
+// Mastercard, American Express or Discover card number and passes the Luhn
+// checksum, so that other numeric IDs are not mistaken for card numbers
+func cardNumberValid(s string) bool {
+	digits := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			digits = append(digits, s[i])
+		}
+	}
+
+	n := len(digits)
+	prefix := func(size int) int {
+		v, _ := strconv.Atoi(string(digits[:size]))
+		return v
+	}
+
+	var issuer bool
+	switch {
+	case digits[0] == '4':
+		issuer = n == 13 || n == 16 || n == 19
+	case prefix(2) >= 51 && prefix(2) <= 55, prefix(4) >= 2221 && prefix(4) <= 2720:
+		issuer = n == 16
+	case prefix(2) == 34, prefix(2) == 37:
+		issuer = n == 15
+	case prefix(4) == 6011, prefix(2) == 65, prefix(3) >= 644 && prefix(3) <= 649:
+		issuer = n >= 16 && n <= 19
+	}
+	return issuer && luhnValid(s)
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
+
+// redactor holds the compiled redaction rules shared by derived handlers
+type redactor struct {
+	keys     []string
+	paths    []string
+	patterns []RedactPattern
+	strategy RedactStrategy
+	mask     string
+	keep     int
+	hashKey  []byte
+}
+
+// newRedactor compiles opts
+func newRedactor(opts *RedactOptions) *redactor {
+	if opts == nil {
+		opts = &RedactOptions{Keys: DefaultRedactKeys, Patterns: DefaultRedactPatterns()}
+	}
+
+	r := &redactor{
+		patterns: opts.Patterns,
+		strategy: opts.Strategy,
+		mask:     opts.Mask,
+		keep:     opts.TruncateKeep,
+		hashKey:  opts.HashKey,
+	}
+	if r.strategy == "" {
+		r.strategy = RedactMask
+	}
+	if r.mask == "" {
+		r.mask = DefaultRedactMask
+	}
+	if r.keep <= 0 {
+		r.keep = defaultTruncateKeep
+	}
+	if r.strategy == RedactHash && len(r.hashKey) == 0 {
+		r.hashKey = make([]byte, sha256.Size)
+		_, _ = rand.Read(r.hashKey)
+	}
+
+	for _, key := range opts.Keys {
+		key = strings.ToLower(strings.TrimSpace(key))
+		switch {
+		case key == "":
+		case strings.Contains(key, "."):
+			r.paths = append(r.paths, key)
+		default:
+			r.keys = append(r.keys, key)
+		}
+	}
+
+	return r
+}
+
+// matchKey reports whether the attribute at groups+key is sensitive
+func (r *redactor) matchKey(groups []string, key string) bool {
+	key = strings.ToLower(key)
+	for _, rule := range r.keys {
+		if ok, _ := path.Match(rule, key); ok {
+			return true
+		}
+	}
+
+	if len(r.paths) == 0 {
+		return false
+	}
+
+	full := strings.ToLower(strings.Join(append(groups[:len(groups):len(groups)], key), "."))
+	for _, rule := range r.paths {
+		if ok, _ := path.Match(rule, full); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// replace applies the strategy to a sensitive value
+func (r *redactor) replace(s string) string {
+	switch r.strategy {
+	case RedactHash:
+		mac := hmac.New(sha256.New, r.hashKey)
+		mac.Write([]byte(s))
+		return "hmac:" + hex.EncodeToString(mac.Sum(nil)[:8])
+	case RedactTruncate:
+		runes := []rune(s)
+		if len(runes) <= r.keep*2 {
+			return r.mask
+		}
+		return string(runes[:r.keep]) + "..."
+	default:
+		return r.mask
+	}
+}
+
+// redactString replaces the substrings of s matched by the patterns
+func (r *redactor) redactString(s string) string {
+	for _, p := range r.patterns {
+		s = p.Regexp.ReplaceAllStringFunc(s, func(match string) string {
+			if p.Validate != nil && !p.Validate(match) {
+				return match
+			}
+			return r.replace(match)
+		})
+	}
+	return s
+}
+
+// redactAttr returns attr with sensitive values replaced, recursing into groups
+func (r *redactor) redactAttr(groups []string, attr slog.Attr) slog.Attr {
+	attr, _ = r.redact(groups, attr)
+	return attr
+}
+
+// redact returns attr with sensitive values replaced, recursing into groups
+// and composite values, and reports whether anything was replaced
+func (r *redactor) redact(groups []string, attr slog.Attr) (slog.Attr, bool) {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Key != "" && r.matchKey(groups, attr.Key) {
+		return slog.String(attr.Key, r.replace(valueString(attr.Value))), true
+	}
+
+	// Inlined groups (empty key) don't add a path segment
+	inner := groups
+	if attr.Key != "" {
+		inner = append(groups[:len(groups):len(groups)], attr.Key)
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		members := attr.Value.Group()
+		redacted := make([]slog.Attr, len(members))
+		changed := false
+		for i, member := range members {
+			var c bool
+			redacted[i], c = r.redact(inner, member)
+			changed = changed || c
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(redacted...)}, changed
+	case slog.KindString:
+		if len(r.patterns) > 0 {
+			if s := attr.Value.String(); r.redactString(s) != s {
+				return slog.String(attr.Key, r.redactString(s)), true
+			}
+		}
+	case slog.KindAny:
+		// Errors and Stringers are formatted as text by the handlers, so check their text too
+		switch v := attr.Value.Any().(type) {
+		case error:
+			if s := v.Error(); len(r.patterns) > 0 && r.redactString(s) != s {
+				return slog.String(attr.Key, r.redactString(s)), true
+			}
+		case fmt.Stringer:
+			if s := v.String(); len(r.patterns) > 0 && r.redactString(s) != s {
+				return slog.String(attr.Key, r.redactString(s)), true
+			}
+		default:
+			if value, ok := r.redactComposite(inner, v); ok {
+				return slog.Attr{Key: attr.Key, Value: value}, true
+			}
+		}
+	}
+
+	return attr, false
+}
+
+// redactComposite converts the struct, map, slice or array v to a group and
+// redacts its members, as the handlers would format v without redaction.
+// Structs are converted as by Struct. It returns false if nothing in v is
+// sensitive, in which case v is logged as it is.
+func (r *redactor) redactComposite(groups []string, v any) (slog.Value, bool) {
+	if len(groups) >= maxStructDepth {
+		return slog.Value{}, false
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return slog.Value{}, false
+		}
+		rv = rv.Elem()
+	}
+
+	var members []slog.Attr
+	switch rv.Kind() {
+	case reflect.Struct:
+		if rv.Type() == timeType {
+			return slog.Value{}, false
+		}
+		members = structValue(rv, 0).Group()
+	case reflect.Map:
+		members = make([]slog.Attr, 0, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			members = append(members, slog.Any(fmt.Sprint(iter.Key().Interface()), iter.Value().Interface()))
+		}
+		slices.SortFunc(members, func(a, b slog.Attr) int { return strings.Compare(a.Key, b.Key) })
+	case reflect.Slice, reflect.Array:
+		// Byte slices are formatted as text or base64, not as elements
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return slog.Value{}, false
+		}
+		members = make([]slog.Attr, rv.Len())
+		for i := range members {
+			members[i] = slog.Any(strconv.Itoa(i), rv.Index(i).Interface())
+		}
+	default:
+		return slog.Value{}, false
+	}
+
+	changed := false
+	for i, member := range members {
+		var c bool
+		members[i], c = r.redact(groups, member)
+		changed = changed || c
+	}
+	if !changed {
+		return slog.Value{}, false
+	}
+
+	return slog.GroupValue(members...), true
+}
+
+// valueString formats a resolved value for hashing or truncation
+func valueString(v slog.Value) string {
+	if v.Kind() == slog.KindString {
+		return v.String()
+	}
+	return fmt.Sprint(v.Any())
+}
+
+// RedactHandler is a slog.Handler that masks sensitive attribute values by key
+// and by value pattern before passing records to the next handler.
+type RedactHandler struct {
+	next     slog.Handler
+	redactor *redactor
+	groups   []string
+}
+
+// NewRedactHandler creates a new RedactHandler wrapping next.
+// If opts is nil, DefaultRedactKeys and DefaultRedactPatterns are used.
+func NewRedactHandler(next slog.Handler, opts *RedactOptions) *RedactHandler {
+	return &RedactHandler{
+		next:     next,
+		redactor: newRedactor(opts),
+	}
+}
+
+// Enabled implements slog.Handler.
+func (h *RedactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+//
+//nolint:gocritic // Cannot change signature due to interface contract
+func (h *RedactHandler) Handle(ctx context.Context, r slog.Record) error {
+	msg := r.Message
+	if len(h.redactor.patterns) > 0 {
+		msg = h.redactor.redactString(msg)
+	}
+
+	redacted := slog.NewRecord(r.Time, r.Level, msg, r.PC)
+	r.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(h.redactor.redactAttr(h.groups, attr))
+		return true
+	})
+
+	return h.next.Handle(ctx, redacted)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *RedactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = h.redactor.redactAttr(h.groups, attr)
+	}
+
+	return &RedactHandler{
+		next:     h.next.WithAttrs(redacted),
+		redactor: h.redactor,
+		groups:   h.groups,
+	}
+}
+
+// WithGroup implements slog.Handler.
+func (h *RedactHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	groups := make([]string, len(h.groups)+1)
+	copy(groups, h.groups)
+	groups[len(h.groups)] = name
+
+	return &RedactHandler{
+		next:     h.next.WithGroup(name),
+		redactor: h.redactor,
+		groups:   groups,
+	}
+}
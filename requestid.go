@@ -0,0 +1,25 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// MaxRequestIDLength is the longest incoming request ID accepted by the
+// httplog and grpclog middleware, longer ones are replaced
+const MaxRequestIDLength = 128
+
+// requestIDSize is the number of random bytes in a generated request ID
+const requestIDSize = 16
+
+// NewRequestID returns a random request ID of 32 hex characters
+func NewRequestID() string {
+	var b [requestIDSize]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// ValidRequestID reports whether an incoming request ID can be used as is
+func ValidRequestID(id string) bool {
+	return id != "" && len(id) <= MaxRequestIDLength
+}
@@ -0,0 +1,25 @@
+package logger
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRequestID(t *testing.T) {
+	id := NewRequestID()
+	assert.Len(t, id, 2*requestIDSize)
+	_, err := hex.DecodeString(id)
+	require.NoError(t, err)
+	assert.NotEqual(t, id, NewRequestID())
+}
+
+func TestValidRequestID(t *testing.T) {
+	assert.True(t, ValidRequestID("req-1"))
+	assert.True(t, ValidRequestID(strings.Repeat("a", MaxRequestIDLength)))
+	assert.False(t, ValidRequestID(""))
+	assert.False(t, ValidRequestID(strings.Repeat("a", MaxRequestIDLength+1)))
+}
@@ -0,0 +1,314 @@
+package logger
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Sampler decides whether a record is kept
+type Sampler interface {
+	// Sample reports whether the record should be kept
+	Sample(ctx context.Context, r *slog.Record) bool
+}
+
+// SamplerFunc adapts a function to the Sampler interface
+type SamplerFunc func(ctx context.Context, r *slog.Record) bool
+
+// Sample implements Sampler.
+func (f SamplerFunc) Sample(ctx context.Context, r *slog.Record) bool {
+	return f(ctx, r)
+}
+
+// burstCounters is the number of counters used by a BurstSampler
+const burstCounters = 4096
+
+// BurstSampler keeps the first N records per interval for each level and
+// message, then every Mth record, like zap's sampler. Messages are hashed into
+// a fixed number of counters, so memory use is bounded.
+type BurstSampler struct {
+	first      uint64
+	thereafter uint64
+	tick       time.Duration
+	counters   [burstCounters]burstCounter
+}
+
+// burstCounter counts records for one hash bucket within the current interval
+type burstCounter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+}
+
+// NewBurstSampler creates a new BurstSampler. A thereafter of 0 drops every
+// record after the first ones in each interval.
+func NewBurstSampler(first, thereafter int, tick time.Duration) *BurstSampler {
+	if tick <= 0 {
+		tick = time.Second
+	}
+	return &BurstSampler{
+		first:      uint64(max(first, 0)),
+		thereafter: uint64(max(thereafter, 0)),
+		tick:       tick,
+	}
+}
+
+// Sample implements Sampler.
+func (s *BurstSampler) Sample(_ context.Context, r *slog.Record) bool {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte{byte(r.Level)})
+	_, _ = h.Write([]byte(r.Message))
+
+	c := &s.counters[h.Sum32()%burstCounters]
+	n := c.inc(r.Time, s.tick)
+
+	if n <= s.first {
+		return true
+	}
+	return s.thereafter > 0 && (n-s.first)%s.thereafter == 0
+}
+
+// inc increments the counter, resetting it when the interval has passed
+func (c *burstCounter) inc(t time.Time, tick time.Duration) uint64 {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	now := t.UnixNano()
+
+	resetAt := c.resetAt.Load()
+	if now > resetAt && c.resetAt.CompareAndSwap(resetAt, now+tick.Nanoseconds()) {
+		c.count.Store(1)
+		return 1
+	}
+
+	return c.count.Add(1)
+}
+
+// RandomSampler keeps each record with a fixed probability
+type RandomSampler struct {
+	rate float64
+}
+
+// NewRandomSampler creates a new RandomSampler keeping the given fraction of records
+func NewRandomSampler(rate float64) *RandomSampler {
+	return &RandomSampler{rate: min(max(rate, 0), 1)}
+}
+
+// Sample implements Sampler.
+func (s *RandomSampler) Sample(context.Context, *slog.Record) bool {
+	return rand.Float64() < s.rate
+}
+
+// RateLimitSampler keeps records while tokens are available in a token bucket
+type RateLimitSampler struct {
+	mu       sync.Mutex
+	rate     float64
+	burst    float64
+	tokens   float64
+	lastFill time.Time
+	now      func() time.Time
+}
+
+// NewRateLimitSampler creates a new RateLimitSampler refilling perSecond tokens
+// per second up to burst tokens
+func NewRateLimitSampler(perSecond float64, burst int) *RateLimitSampler {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimitSampler{
+		rate:   perSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}
+}
+
+// Sample implements Sampler.
+func (s *RateLimitSampler) Sample(context.Context, *slog.Record) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if !s.lastFill.IsZero() {
+		s.tokens = min(s.burst, s.tokens+now.Sub(s.lastFill).Seconds()*s.rate)
+	}
+	s.lastFill = now
+
+	if s.tokens < 1 {
+		return false
+	}
+	s.tokens--
+	return true
+}
+
+// allSamplers keeps a record only if every sampler keeps it
+type allSamplers []Sampler
+
+// Sample implements Sampler.
+func (s allSamplers) Sample(ctx context.Context, r *slog.Record) bool {
+	for _, sampler := range s {
+		if !sampler.Sample(ctx, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// SamplingOptions configures a SamplingHandler
+type SamplingOptions struct {
+	// Levels holds samplers for specific levels. Records at or above
+	// LevelError are only sampled if their level is listed here.
+	Levels map[slog.Level]Sampler
+
+	// Default samples records below LevelError without a level sampler
+	Default Sampler
+}
+
+// SamplingStats holds the counters of a SamplingHandler
+type SamplingStats struct {
+	// Kept is the number of records passed to the next handler
+	Kept uint64
+
+	// Dropped is the number of records dropped by sampling
+	Dropped uint64
+
+	// DroppedByLevel is the number of dropped records per level
+	DroppedByLevel map[slog.Level]uint64
+}
+
+// samplingState holds the samplers and counters shared by derived handlers
+type samplingState struct {
+	opts    SamplingOptions
+	kept    atomic.Uint64
+	dropped atomic.Uint64
+	mu      sync.Mutex
+	byLevel map[slog.Level]uint64
+}
+
+// SamplingHandler is a slog.Handler that drops records rejected by its
+// samplers to protect hot paths.
+type SamplingHandler struct {
+	next  slog.Handler
+	state *samplingState
+}
+
+// NewSamplingHandler creates a new SamplingHandler wrapping next.
+func NewSamplingHandler(next slog.Handler, opts *SamplingOptions) *SamplingHandler {
+	if opts == nil {
+		opts = &SamplingOptions{}
+	}
+	return &SamplingHandler{
+		next: next,
+		state: &samplingState{
+			opts:    *opts,
+			byLevel: make(map[slog.Level]uint64),
+		},
+	}
+}
+
+// Enabled implements slog.Handler.
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+//
+//nolint:gocritic // Cannot change signature due to interface contract
+func (h *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if sampler := h.state.sampler(r.Level); sampler != nil && !sampler.Sample(ctx, &r) {
+		h.state.dropped.Add(1)
+		h.state.mu.Lock()
+		h.state.byLevel[r.Level]++
+		h.state.mu.Unlock()
+		return nil
+	}
+
+	h.state.kept.Add(1)
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{next: h.next.WithAttrs(attrs), state: h.state}
+}
+
+// WithGroup implements slog.Handler.
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{next: h.next.WithGroup(name), state: h.state}
+}
+
+// Stats returns the sampling counters, shared by all handlers derived from this one
+func (h *SamplingHandler) Stats() SamplingStats {
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+
+	byLevel := make(map[slog.Level]uint64, len(h.state.byLevel))
+	for level, n := range h.state.byLevel {
+		byLevel[level] = n
+	}
+
+	return SamplingStats{
+		Kept:           h.state.kept.Load(),
+		Dropped:        h.state.dropped.Load(),
+		DroppedByLevel: byLevel,
+	}
+}
+
+// sampler returns the sampler for level, or nil if records are always kept
+func (s *samplingState) sampler(level slog.Level) Sampler {
+	if sampler, ok := s.opts.Levels[level]; ok {
+		return sampler
+	}
+	if level >= slog.LevelError {
+		return nil
+	}
+	return s.opts.Default
+}
+
+// SamplingConfig configures sampling for one level. Every non-zero mechanism
+// applies, and a record is kept only if all of them keep it.
+type SamplingConfig struct {
+	// Initial is the number of records per message kept each tick
+	Initial int
+
+	// Thereafter keeps every Mth record per message after Initial
+	Thereafter int
+
+	// Tick is the burst interval, defaults to one second
+	Tick time.Duration
+
+	// Rate is the probability of keeping a record
+	Rate float64
+
+	// PerSecond is the token bucket refill rate
+	PerSecond float64
+
+	// Burst is the token bucket size
+	Burst int
+}
+
+// Sampler creates the sampler described by the config, or nil if it is empty
+func (c SamplingConfig) Sampler() Sampler {
+	var samplers allSamplers
+	if c.Initial > 0 || c.Thereafter > 0 {
+		samplers = append(samplers, NewBurstSampler(c.Initial, c.Thereafter, c.Tick))
+	}
+	if c.Rate > 0 {
+		samplers = append(samplers, NewRandomSampler(c.Rate))
+	}
+	if c.PerSecond > 0 {
+		samplers = append(samplers, NewRateLimitSampler(c.PerSecond, c.Burst))
+	}
+
+	switch len(samplers) {
+	case 0:
+		return nil
+	case 1:
+		return samplers[0]
+	default:
+		return samplers
+	}
+}
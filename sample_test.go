@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBurstSampler(t *testing.T) {
+	sampler := NewBurstSampler(2, 3, time.Minute)
+	now := time.Now()
+
+	kept := 0
+	for range 11 {
+		r := slog.NewRecord(now, slog.LevelInfo, "hot path", 0)
+		if sampler.Sample(context.Background(), &r) {
+			kept++
+		}
+	}
+	// First 2, then the 5th, 8th and 11th
+	assert.Equal(t, 5, kept)
+
+	other := slog.NewRecord(now, slog.LevelInfo, "other message", 0)
+	assert.True(t, sampler.Sample(context.Background(), &other))
+
+	later := slog.NewRecord(now.Add(2*time.Minute), slog.LevelInfo, "hot path", 0)
+	assert.True(t, sampler.Sample(context.Background(), &later))
+}
+
+func TestRandomSampler(t *testing.T) {
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "msg", 0)
+	assert.False(t, NewRandomSampler(0).Sample(context.Background(), &r))
+	assert.True(t, NewRandomSampler(1).Sample(context.Background(), &r))
+}
+
+func TestRateLimitSampler(t *testing.T) {
+	now := time.Now()
+	sampler := NewRateLimitSampler(1, 2)
+	sampler.now = func() time.Time { return now }
+
+	r := slog.NewRecord(now, slog.LevelInfo, "msg", 0)
+	assert.True(t, sampler.Sample(context.Background(), &r))
+	assert.True(t, sampler.Sample(context.Background(), &r))
+	assert.False(t, sampler.Sample(context.Background(), &r))
+
+	now = now.Add(time.Second)
+	assert.True(t, sampler.Sample(context.Background(), &r))
+	assert.False(t, sampler.Sample(context.Background(), &r))
+}
+
+func TestSamplingHandler(t *testing.T) {
+	mock := &MockLogger{}
+	dropAll := SamplerFunc(func(context.Context, *slog.Record) bool { return false })
+	handler := NewSamplingHandler(mock, &SamplingOptions{Default: dropAll})
+	log := slog.New(handler)
+
+	log.Debug("dropped")
+	log.Info("dropped")
+	log.Error("kept")
+	log.Log(context.Background(), slog.LevelError+4, "kept")
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 2)
+	assert.Equal(t, slog.LevelError, logs[0].Level)
+
+	stats := handler.Stats()
+	assert.Equal(t, uint64(2), stats.Kept)
+	assert.Equal(t, uint64(2), stats.Dropped)
+	assert.Equal(t, map[slog.Level]uint64{slog.LevelDebug: 1, slog.LevelInfo: 1}, stats.DroppedByLevel)
+
+	// Errors are only sampled when configured explicitly
+	handler = NewSamplingHandler(mock, &SamplingOptions{Levels: map[slog.Level]Sampler{slog.LevelError: dropAll}})
+	slog.New(handler).Error("dropped")
+	assert.Equal(t, uint64(1), handler.Stats().Dropped)
+}
+
+func TestSamplingConfig(t *testing.T) {
+	assert.Nil(t, SamplingConfig{}.Sampler())
+	assert.IsType(t, &BurstSampler{}, SamplingConfig{Initial: 10}.Sampler())
+	assert.IsType(t, allSamplers{}, SamplingConfig{Initial: 10, PerSecond: 5}.Sampler())
+
+	log, err := New(&Config{Level: "info", Sampling: map[string]SamplingConfig{"info": {Initial: 1}}})
+	require.NoError(t, err)
+	assert.IsType(t, &SamplingHandler{}, log.Handler())
+
+	_, err = New(&Config{Level: "info", Sampling: map[string]SamplingConfig{"loud": {Initial: 1}}})
+	require.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// Flusher is implemented by handlers and sinks that buffer records
+type Flusher interface {
+	// Flush writes all buffered records
+	Flush(ctx context.Context) error
+}
+
+// Closer is implemented by handlers and sinks that hold resources
+type Closer interface {
+	// Close flushes buffered records and releases resources
+	Close(ctx context.Context) error
+}
+
+// Logger is a *slog.Logger created by Open that can be flushed and closed
+type Logger struct {
+	*slog.Logger
+
+	// components are the flushable parts of the handler chain, in drain order
+	components []slog.Handler
+
+	// metrics holds the counters if Config.EnableMetrics is set
+	metrics *Metrics
+}
+
+// Metrics returns the logging metrics, or nil if Config.EnableMetrics is not set
+func (l *Logger) Metrics() *Metrics {
+	return l.metrics
+}
+
+// Flush writes the records buffered by every part of the handler chain, in order
+func (l *Logger) Flush(ctx context.Context) error {
+	var errs []error
+	for _, c := range l.components {
+		if f, ok := c.(Flusher); ok {
+			if err := f.Flush(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close drains and closes every part of the handler chain, in order.
+// Parts that can only be flushed are flushed.
+func (l *Logger) Close(ctx context.Context) error {
+	var errs []error
+	for _, c := range l.components {
+		switch h := c.(type) {
+		case Closer:
+			if err := h.Close(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		case Flusher:
+			if err := h.Flush(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Shutdown flushes and closes the logger installed by Init or SetDefaultLogger.
+// The default logger is reset first, so records logged during and after the
+// shutdown are written synchronously by slog.Default instead of being lost,
+// and the slog default and log package output redirected by Init are restored.
+// A default logger set with SetDefault since is kept. Shutdown is a no-op if
+// no logger is installed by Init or SetDefaultLogger.
+func Shutdown(ctx context.Context) error {
+	log, restore := releaseDefault()
+	if log == nil {
+		return nil
+	}
+
+	runRestore(restore)
+	return log.Close(ctx)
+}
+
+// ShutdownOnDone calls Shutdown once ctx is done, allowing it the given
+// timeout, and delivers the result on the returned channel. It fits the
+// signal.NotifyContext pattern:
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+//	defer stop()
+//	done := logger.ShutdownOnDone(ctx, 5*time.Second)
+//	// ... run until ctx is done
+//	<-done
+func ShutdownOnDone(ctx context.Context, timeout time.Duration) <-chan error {
+	done := make(chan error, 1)
+
+	go func() {
+		<-ctx.Done()
+
+		// ctx is already done, so the shutdown gets its own deadline
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+		defer cancel()
+
+		done <- Shutdown(shutdownCtx)
+	}()
+
+	return done
+}
@@ -0,0 +1,178 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink records Flush and Close calls in a shared journal
+type recordingSink struct {
+	*MockLogger
+	name    string
+	journal *[]string
+	mu      *sync.Mutex
+}
+
+func (s *recordingSink) Flush(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*s.journal = append(*s.journal, "flush "+s.name)
+	return nil
+}
+
+func (s *recordingSink) Close(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*s.journal = append(*s.journal, "close "+s.name)
+	return nil
+}
+
+func registerRecordingSinks(t *testing.T, names ...string) (*[]string, map[string]*recordingSink) {
+	t.Helper()
+
+	journal := &[]string{}
+	var journalMu sync.Mutex
+	created := make(map[string]*recordingSink)
+
+	for _, name := range names {
+		sink := &recordingSink{MockLogger: &MockLogger{}, name: name, journal: journal, mu: &journalMu}
+		created[name] = sink
+		RegisterSink(name, func(*Config, *slog.HandlerOptions) (slog.Handler, error) {
+			return sink, nil
+		})
+	}
+
+	t.Cleanup(func() {
+		sinksMu.Lock()
+		defer sinksMu.Unlock()
+		for _, name := range names {
+			delete(sinks, name)
+		}
+	})
+
+	return journal, created
+}
+
+func TestLoggerClose(t *testing.T) {
+	journal, created := registerRecordingSinks(t, "first", "second")
+
+	log, err := Open(&Config{Level: "info", Sinks: []string{"first", "second"}, EnableAsync: true})
+	require.NoError(t, err)
+
+	for range 10 {
+		log.Info("buffered")
+	}
+
+	require.NoError(t, log.Flush(context.Background()))
+	assert.Len(t, created["first"].GetLogs(), 10)
+
+	// The async writer flushes the sinks after each batch as well
+	require.GreaterOrEqual(t, len(*journal), 4)
+	assert.Equal(t, []string{"flush first", "flush second"}, (*journal)[len(*journal)-2:])
+
+	log.Info("drained on close")
+	require.NoError(t, log.Close(context.Background()))
+	assert.Len(t, created["second"].GetLogs(), 11)
+	assert.Equal(t, []string{"flush first", "flush second", "close first", "close second"}, (*journal)[len(*journal)-4:])
+}
+
+func TestShutdown(t *testing.T) {
+	keepErrorStacks(t)
+	journal, _ := registerRecordingSinks(t, "shutdown")
+	resetDefault(t)
+
+	require.NoError(t, Init(&Config{Level: "info", Sinks: []string{"shutdown"}}))
+	require.NoError(t, Shutdown(context.Background()))
+	assert.Equal(t, []string{"close shutdown"}, *journal)
+
+	// A second shutdown has nothing left to close
+	require.NoError(t, Shutdown(context.Background()))
+	assert.Len(t, *journal, 1)
+
+	// Loggers set with SetDefault are not owned by the package
+	SetDefault(NewMockLogger())
+	require.NoError(t, Shutdown(context.Background()))
+}
+
+func TestShutdownResetsDefault(t *testing.T) {
+	keepErrorStacks(t)
+	journal, created := registerRecordingSinks(t, "reset", "replaced", "kept")
+	prev := slog.Default()
+	resetDefault(t)
+
+	require.NoError(t, Init(&Config{Level: "info", Sinks: []string{"reset"}, SetSlogDefault: true}))
+	assert.Same(t, Default(), slog.Default())
+
+	require.NoError(t, Shutdown(context.Background()))
+	assert.Same(t, prev, slog.Default())
+	assert.Same(t, prev, Default())
+
+	// Records logged after the shutdown do not reach the closed chain
+	before := len(created["reset"].GetLogs())
+	Info("after shutdown")
+	assert.Len(t, created["reset"].GetLogs(), before)
+
+	// Installing another logger closes the previous one
+	require.NoError(t, Init(&Config{Level: "info", Sinks: []string{"replaced"}}))
+	require.NoError(t, Init(&Config{Level: "info", Sinks: []string{"kept"}}))
+	assert.Equal(t, []string{"close reset", "close replaced"}, *journal)
+}
+
+func TestSetDefaultKeepsInstalled(t *testing.T) {
+	keepErrorStacks(t)
+	journal, created := registerRecordingSinks(t, "installed")
+	resetDefault(t)
+
+	require.NoError(t, Init(&Config{Level: "info", Sinks: []string{"installed"}}))
+	child := Default().With("component", "worker")
+
+	// SetDefault does not close the installed logger, so children held
+	// elsewhere keep writing to it until Shutdown
+	mock := NewMockLogger()
+	SetDefault(mock)
+	assert.Same(t, mock, Default())
+	assert.Empty(t, *journal)
+
+	child.Info("held elsewhere")
+	logs := created["installed"].GetLogs()
+	assert.Equal(t, "held elsewhere", logs[len(logs)-1].Message)
+
+	require.NoError(t, Shutdown(context.Background()))
+	assert.Equal(t, []string{"close installed"}, *journal)
+	assert.Same(t, mock, Default())
+}
+
+// resetDefault shuts down the logger installed by the test and clears the
+// default logger when the test ends
+func resetDefault(t *testing.T) {
+	t.Cleanup(func() {
+		_ = Shutdown(context.Background())
+		SetDefault(nil)
+	})
+}
+
+func TestNewRejectsAsync(t *testing.T) {
+	_, err := New(&Config{Level: "info", EnableAsync: true})
+	require.ErrorIs(t, err, errNewAsync)
+}
+
+func TestShutdownOnDone(t *testing.T) {
+	keepErrorStacks(t)
+	journal, _ := registerRecordingSinks(t, "signal")
+	resetDefault(t)
+
+	require.NoError(t, Init(&Config{Level: "info", Sinks: []string{"signal"}}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := ShutdownOnDone(ctx, time.Second)
+	cancel()
+
+	require.NoError(t, <-done)
+	assert.Equal(t, []string{"close signal"}, *journal)
+}
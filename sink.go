@@ -0,0 +1,227 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Sink names
+const (
+	SinkStdout  = "stdout"
+	SinkStderr  = "stderr"
+	SinkBackend = "backend"
+)
+
+// SinkFactory creates the handler a sink writes records to.
+// The options carry the level and source settings derived from the config.
+type SinkFactory func(cfg *Config, opts *slog.HandlerOptions) (slog.Handler, error)
+
+var (
+	// sinks holds the registered sink factories by name
+	sinks = map[string]SinkFactory{
+		SinkStdout: writerSink(os.Stdout),
+		SinkStderr: writerSink(os.Stderr),
+	}
+
+	// sinksMu protects sinks
+	sinksMu sync.RWMutex
+)
+
+// RegisterSink registers a sink factory under the given name, so it can be
+// selected with Config.Sinks. Registering an existing name replaces it.
+func RegisterSink(name string, factory SinkFactory) {
+	if factory == nil {
+		return
+	}
+
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	sinks[strings.ToLower(name)] = factory
+}
+
+// sinkNames returns the normalized names of the sinks selected in cfg
+func sinkNames(cfg *Config) []string {
+	if cfg.Backend != nil {
+		return []string{SinkBackend}
+	}
+	if len(cfg.Sinks) == 0 {
+		return []string{SinkStdout}
+	}
+
+	names := make([]string, len(cfg.Sinks))
+	for i, name := range cfg.Sinks {
+		names[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+	return names
+}
+
+// newSinkHandlers creates the handlers for the sinks selected in cfg, or the backend
+func newSinkHandlers(cfg *Config, opts *slog.HandlerOptions) ([]slog.Handler, error) {
+	if cfg.Backend != nil {
+		handler, err := cfg.Backend(cfg, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create backend: %w", err)
+		}
+		return []slog.Handler{handler}, nil
+	}
+
+	names := sinkNames(cfg)
+
+	handlers := make([]slog.Handler, 0, len(names))
+	for _, name := range names {
+		sinksMu.RLock()
+		factory, ok := sinks[name]
+		sinksMu.RUnlock()
+
+		if !ok {
+			closeAll(handlers)
+			return nil, fmt.Errorf("unknown sink %q (registered: %s)", name, strings.Join(registeredSinks(), ", "))
+		}
+
+		handler, err := factory(cfg, opts)
+		if err != nil {
+			closeAll(handlers)
+			return nil, fmt.Errorf("failed to create sink %q: %w", name, err)
+		}
+		handlers = append(handlers, handler)
+	}
+
+	return handlers, nil
+}
+
+// closeAll closes the already created sinks when creating another one fails
+func closeAll(handlers []slog.Handler) {
+	for _, handler := range handlers {
+		if c, ok := handler.(Closer); ok {
+			_ = c.Close(context.Background())
+		}
+	}
+}
+
+// sinkFlusher flushes the sinks that buffer records
+type sinkFlusher []slog.Handler
+
+// Flush implements Flusher.
+func (s sinkFlusher) Flush(ctx context.Context) error {
+	var errs []error
+	for _, handler := range s {
+		if f, ok := handler.(Flusher); ok {
+			if err := f.Flush(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// registeredSinks returns the sorted names of the registered sinks
+func registeredSinks() []string {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+
+	names := make([]string, 0, len(sinks))
+	for name := range sinks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// writerSink returns a sink factory writing to w in the configured format,
+// counting the bytes written
+func writerSink(w io.Writer) SinkFactory {
+	return func(cfg *Config, opts *slog.HandlerOptions) (slog.Handler, error) {
+		cw := &countingWriter{w: w}
+		return &writerHandler{Handler: newWriterHandler(cw, cfg, opts), w: cw}, nil
+	}
+}
+
+// newWriterHandler creates a handler writing to w based on the format and color settings
+func newWriterHandler(w io.Writer, cfg *Config, opts *slog.HandlerOptions) slog.Handler {
+	// Check if we should use colored output
+	useColors := cfg.EnableColors || isLocalEnvironment(cfg.Environment)
+
+	switch {
+	case cfg.Format == FormatConsole && useColors:
+		// Use colored handler for console format in local environment
+		return NewColoredHandler(w, opts, false)
+	case cfg.Format == FormatJSON && useColors:
+		// Use colored JSON handler
+		return NewColoredHandler(w, opts, true)
+	case cfg.Format == FormatConsole:
+		// Use standard text handler
+		return slog.NewTextHandler(w, opts)
+	default:
+		// Use standard JSON handler
+		return slog.NewJSONHandler(w, opts)
+	}
+}
+
+// MultiHandler is a slog.Handler that passes each record to several handlers.
+// Records replayed by a FlightRecorderHandler are passed to all of them.
+type MultiHandler struct {
+	handlers []slog.Handler
+}
+
+// NewMultiHandler creates a new MultiHandler that fans out to handlers.
+func NewMultiHandler(handlers ...slog.Handler) *MultiHandler {
+	return &MultiHandler{handlers: handlers}
+}
+
+// Handlers returns the handlers records are passed to
+func (h *MultiHandler) Handlers() []slog.Handler {
+	return h.handlers
+}
+
+// Enabled implements slog.Handler.
+func (h *MultiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle implements slog.Handler.
+//
+//nolint:gocritic // Cannot change signature due to interface contract
+func (h *MultiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	backfill := IsBackfill(ctx)
+	for _, handler := range h.handlers {
+		if !backfill && !handler.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := handler.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *MultiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithAttrs(attrs)
+	}
+	return &MultiHandler{handlers: handlers}
+}
+
+// WithGroup implements slog.Handler.
+func (h *MultiHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithGroup(name)
+	}
+	return &MultiHandler{handlers: handlers}
+}
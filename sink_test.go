@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSinks(t *testing.T) {
+	var buf bytes.Buffer
+	RegisterSink("buffer", func(cfg *Config, opts *slog.HandlerOptions) (slog.Handler, error) {
+		return newWriterHandler(&buf, cfg, opts), nil
+	})
+	t.Cleanup(func() {
+		sinksMu.Lock()
+		delete(sinks, "buffer")
+		sinksMu.Unlock()
+	})
+
+	log, err := New(&Config{Level: "info", Format: FormatJSON, Sinks: []string{"Buffer"}})
+	require.NoError(t, err)
+
+	log.Info("to buffer", "key", "value")
+	assert.Contains(t, buf.String(), `"msg":"to buffer"`)
+
+	_, err = New(&Config{Level: "info", Sinks: []string{"missing"}})
+	require.ErrorContains(t, err, `unknown sink "missing"`)
+}
+
+func TestBackend(t *testing.T) {
+	mock := &MockLogger{}
+	log, err := Open(&Config{
+		Level:         "info",
+		Sinks:         []string{"missing"},
+		EnableMetrics: true,
+		Backend: func(_ *Config, opts *slog.HandlerOptions) (slog.Handler, error) {
+			assert.Equal(t, slog.LevelInfo, opts.Level)
+			return mock, nil
+		},
+	})
+	require.NoError(t, err)
+
+	log.Info("to backend")
+	require.Len(t, mock.GetLogs(), 1)
+	assert.Equal(t, "to backend", mock.GetLogs()[0].Message)
+	assert.Equal(t, uint64(1), log.Metrics().Value(MetricSinkRecords, "sink", SinkBackend, "level", "info"))
+
+	_, err = New(&Config{Level: "info", Backend: func(*Config, *slog.HandlerOptions) (slog.Handler, error) {
+		return nil, assert.AnError
+	}})
+	require.ErrorIs(t, err, assert.AnError)
+}
+
+func TestMultiHandler(t *testing.T) {
+	var debug, info bytes.Buffer
+	handler := NewMultiHandler(
+		slog.NewTextHandler(&debug, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		slog.NewTextHandler(&info, &slog.HandlerOptions{Level: slog.LevelInfo}),
+	)
+	log := slog.New(handler).With("service", "api")
+
+	assert.True(t, handler.Enabled(context.Background(), slog.LevelDebug))
+
+	log.Debug("debug message")
+	log.Info("info message")
+
+	assert.Contains(t, debug.String(), "debug message")
+	assert.Contains(t, debug.String(), "info message")
+	assert.NotContains(t, info.String(), "debug message")
+	assert.Contains(t, info.String(), "service=api")
+}
@@ -0,0 +1,138 @@
+package logger
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// StdLogPrefixKey is the attribute holding the prefix of a bridged log.Logger
+const StdLogPrefixKey = "prefix"
+
+// maxStdLogDepth bounds the stack searched for the caller of the log package
+const maxStdLogDepth = 16
+
+// stdLogWriter is the io.Writer of a bridged log.Logger, turning each
+// output line into a record
+type stdLogWriter struct {
+	logger *slog.Logger
+	level  slog.Level
+	src    *log.Logger
+}
+
+// NewStdLogger returns a *log.Logger whose output is logged to l at level
+func NewStdLogger(l *slog.Logger, level slog.Level) *log.Logger {
+	std := log.New(nil, "", 0)
+	BridgeStdLogger(std, l, level)
+	return std
+}
+
+// BridgeStdLogger redirects the output of std to l at level. The prefix,
+// date, time and file headers added by the flags of std are parsed, the
+// prefix is logged as the StdLogPrefixKey attribute and the caller of the
+// log package is recorded as the source.
+func BridgeStdLogger(std *log.Logger, l *slog.Logger, level slog.Level) {
+	std.SetOutput(&stdLogWriter{logger: l, level: level, src: std})
+}
+
+// RedirectStdLog redirects the standard logger of the log package to l at
+// level and returns a function restoring its previous output and flags
+func RedirectStdLog(l *slog.Logger, level slog.Level) (restore func()) {
+	std := log.Default()
+	output, flags, prefix := std.Writer(), std.Flags(), std.Prefix()
+
+	// Time and file are recorded by the handler, so the log package need not format them
+	std.SetFlags(flags &^ (log.Ldate | log.Ltime | log.Lmicroseconds | log.Llongfile | log.Lshortfile))
+	BridgeStdLogger(std, l, level)
+
+	return func() {
+		std.SetOutput(output)
+		std.SetFlags(flags)
+		std.SetPrefix(prefix)
+	}
+}
+
+// Write implements io.Writer.
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	ctx := context.Background()
+	if !w.logger.Enabled(ctx, w.level) {
+		return len(p), nil
+	}
+
+	prefix, msg := parseStdLogLine(string(p), w.src.Flags(), w.src.Prefix())
+
+	r := slog.NewRecord(time.Now(), w.level, msg, stdLogCaller())
+	if prefix != "" {
+		r.AddAttrs(slog.String(StdLogPrefixKey, prefix))
+	}
+	if err := w.logger.Handler().Handle(ctx, r); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// parseStdLogLine strips the headers written by a log.Logger with the given
+// flags and prefix from line, returning the trimmed prefix and the message
+func parseStdLogLine(line string, flags int, prefix string) (string, string) {
+	line = strings.TrimSuffix(line, "\n")
+
+	if flags&log.Lmsgprefix == 0 {
+		line = strings.TrimPrefix(line, prefix)
+	}
+	if flags&log.Ldate != 0 {
+		line = skipField(line, len("2006/01/02 "))
+	}
+	if flags&(log.Ltime|log.Lmicroseconds) != 0 {
+		if flags&log.Lmicroseconds != 0 {
+			line = skipField(line, len("15:04:05.000000 "))
+		} else {
+			line = skipField(line, len("15:04:05 "))
+		}
+	}
+	if flags&(log.Lshortfile|log.Llongfile) != 0 {
+		// The file ends at the first ": " after the line number
+		if i := strings.Index(line, ": "); i >= 0 {
+			line = line[i+2:]
+		}
+	}
+	if flags&log.Lmsgprefix != 0 {
+		line = strings.TrimPrefix(line, prefix)
+	}
+
+	return strings.TrimSpace(prefix), line
+}
+
+// skipField removes a fixed width header field from line if it is long enough
+func skipField(line string, width int) string {
+	if len(line) < width {
+		return line
+	}
+	return line[width:]
+}
+
+// stdLogCaller returns the program counter of the function that called the
+// log package, found as the first frame after the log package frames
+func stdLogCaller() uintptr {
+	var pcs [maxStdLogDepth]uintptr
+	n := runtime.Callers(3, pcs[:])
+
+	inLog := false
+	for _, pc := range pcs[:n] {
+		fn := runtime.FuncForPC(pc - 1)
+		if fn == nil {
+			continue
+		}
+		if strings.HasPrefix(fn.Name(), "log.") {
+			inLog = true
+			continue
+		}
+		if inLog {
+			return pc
+		}
+	}
+	return 0
+}
@@ -0,0 +1,167 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var m map[string]any
+		require.NoError(t, dec.Decode(&m))
+		records = append(records, m)
+	}
+	return records
+}
+
+func TestNewStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true}))
+
+	std := NewStdLogger(l, slog.LevelWarn)
+	std.Printf("disk %d%% full", 91)
+
+	records := decodeRecords(t, &buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "WARN", records[0]["level"])
+	assert.Equal(t, "disk 91% full", records[0]["msg"])
+	assert.NotContains(t, records[0], StdLogPrefixKey)
+
+	source, ok := records[0]["source"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "github.com/legrch/logger.TestNewStdLogger", source["function"])
+}
+
+func TestBridgeStdLogger(t *testing.T) {
+	mock := &MockLogger{}
+	std := log.New(nil, "[db] ", log.LstdFlags|log.Lmicroseconds|log.Lshortfile)
+	BridgeStdLogger(std, slog.New(mock), slog.LevelInfo)
+
+	std.Println("connected: pool=4")
+	std.SetFlags(std.Flags() | log.Lmsgprefix)
+	std.Print("closed")
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 2)
+	assert.Equal(t, "connected: pool=4", logs[0].Message)
+	assert.Equal(t, []slog.Attr{slog.String(StdLogPrefixKey, "[db]")}, logs[0].Attrs)
+	assert.Equal(t, "closed", logs[1].Message)
+}
+
+func TestParseStdLogLine(t *testing.T) {
+	tests := []struct {
+		name       string
+		line       string
+		flags      int
+		prefix     string
+		wantPrefix string
+		wantMsg    string
+	}{
+		{"plain", "hello\n", 0, "", "", "hello"},
+		{"std flags", "2024/01/02 15:04:05 hello\n", log.LstdFlags, "", "", "hello"},
+		{"micro and file", "15:04:05.123456 main.go:12: a: b\n", log.Lmicroseconds | log.Lshortfile, "", "", "a: b"},
+		{"prefix", "app: 2024/01/02 hello\n", log.Ldate, "app: ", "app:", "hello"},
+		{"msg prefix", "2024/01/02 app: hello\n", log.Ldate | log.Lmsgprefix, "app: ", "app:", "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefix, msg := parseStdLogLine(tt.line, tt.flags, tt.prefix)
+			assert.Equal(t, tt.wantPrefix, prefix)
+			assert.Equal(t, tt.wantMsg, msg)
+		})
+	}
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true}))
+
+	prevOutput, prevFlags := log.Writer(), log.Flags()
+	t.Cleanup(func() {
+		log.SetOutput(prevOutput)
+		log.SetFlags(prevFlags)
+	})
+
+	var previous bytes.Buffer
+	log.SetOutput(&previous)
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+
+	restore := RedirectStdLog(l, slog.LevelInfo)
+	log.Printf("from %s", "library")
+	restore()
+	log.Print("after restore")
+
+	records := decodeRecords(t, &buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "from library", records[0]["msg"])
+	assert.Equal(t, "github.com/legrch/logger.TestRedirectStdLog",
+		records[0]["source"].(map[string]any)["function"])
+
+	assert.Equal(t, log.LstdFlags|log.Lshortfile, log.Flags())
+	assert.Contains(t, previous.String(), "after restore")
+}
+
+func TestInitSlogDefault(t *testing.T) {
+	keepErrorStacks(t)
+	prev := slog.Default()
+	prevOutput, prevFlags := log.Writer(), log.Flags()
+	t.Cleanup(func() {
+		_ = Shutdown(context.Background())
+		slog.SetDefault(prev)
+		log.SetOutput(prevOutput)
+		log.SetFlags(prevFlags)
+		SetDefault(nil)
+	})
+
+	mock := &MockLogger{}
+	RegisterSink("stdlog", func(*Config, *slog.HandlerOptions) (slog.Handler, error) {
+		return mock, nil
+	})
+	t.Cleanup(func() {
+		sinksMu.Lock()
+		defer sinksMu.Unlock()
+		delete(sinks, "stdlog")
+	})
+
+	require.NoError(t, Init(&Config{
+		Level:          "info",
+		Sinks:          []string{"stdlog"},
+		SetSlogDefault: true,
+		RedirectStdLog: true,
+		StdLogLevel:    "warn",
+	}))
+	assert.Same(t, Default(), slog.Default())
+
+	log.Print("legacy")
+
+	logs := mock.GetLogs()
+	require.NotEmpty(t, logs)
+	last := logs[len(logs)-1]
+	assert.Equal(t, "legacy", last.Message)
+	assert.Equal(t, slog.LevelWarn, last.Level)
+
+	// Shutdown restores the output of the log package
+	require.NoError(t, Shutdown(context.Background()))
+	var previous bytes.Buffer
+	log.SetOutput(&previous)
+	require.NoError(t, Init(&Config{Level: "info", Sinks: []string{"stdlog"}, RedirectStdLog: true}))
+	require.NoError(t, Shutdown(context.Background()))
+	count := len(mock.GetLogs())
+	log.Print("after shutdown")
+	assert.Len(t, mock.GetLogs(), count)
+	assert.Contains(t, previous.String(), "after shutdown")
+
+	assert.Error(t, Init(&Config{Level: "info", RedirectStdLog: true, StdLogLevel: "loud"}))
+}
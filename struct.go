@@ -0,0 +1,259 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StructTag is the struct tag key read by Struct.
+//
+// Supported options, comma separated:
+//
+//	log:"-"            skip the field
+//	log:"redact"       replace the value with DefaultRedactMask
+//	log:"name=userId"  log the field under another key
+//	log:"omitempty"    skip the field if it has its zero value
+const StructTag = "log"
+
+// Struct returns a slog.LogValuer that logs the struct v as a group,
+// honoring the `log` struct tags of its fields. Nested and embedded structs
+// are handled recursively, as are the structs in slices, arrays and maps,
+// which are logged as groups keyed by index or map key. Structs nested
+// deeper than maxStructDepth are logged as MaxDepthValue. Other values are
+// logged as they are.
+func Struct(v any) slog.LogValuer {
+	return structValuer{v: v}
+}
+
+// structValuer implements slog.LogValuer for Struct
+type structValuer struct {
+	v any
+}
+
+// LogValue implements slog.LogValuer.
+func (s structValuer) LogValue() slog.Value {
+	return structValue(reflect.ValueOf(s.v), 0)
+}
+
+// fieldPlan describes how a single struct field is logged
+type fieldPlan struct {
+	index     int
+	name      string
+	redact    bool
+	omitEmpty bool
+	inline    bool
+}
+
+// structPlans caches the field plans per struct type
+var structPlans sync.Map // map[reflect.Type][]fieldPlan
+
+// timeType is the reflect type of time.Time, which is logged as a value
+var timeType = reflect.TypeFor[time.Time]()
+
+// logValuerType is the reflect type of slog.LogValuer
+var logValuerType = reflect.TypeFor[slog.LogValuer]()
+
+// maxStructDepth bounds the recursion into nested structs, guarding against cycles
+const maxStructDepth = 10
+
+// MaxDepthValue replaces the structs nested deeper than Struct recurses
+const MaxDepthValue = "<max depth>"
+
+// structValue converts v to a slog.Value using the cached plan of its type
+func structValue(v reflect.Value, depth int) slog.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return slog.AnyValue(nil)
+		}
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		return slog.AnyValue(nil)
+	}
+	if v.Kind() != reflect.Struct || v.Type() == timeType {
+		return fieldValue(v, depth)
+	}
+	if depth >= maxStructDepth {
+		return slog.StringValue(MaxDepthValue)
+	}
+
+	plan := structPlan(v.Type())
+	attrs := make([]slog.Attr, 0, len(plan))
+	for _, f := range plan {
+		fv := v.Field(f.index)
+
+		if f.omitEmpty && fv.IsZero() {
+			continue
+		}
+		if f.inline && fv.Kind() == reflect.Pointer && fv.IsNil() {
+			continue
+		}
+		if f.redact {
+			attrs = append(attrs, slog.String(f.name, DefaultRedactMask))
+			continue
+		}
+
+		attrs = append(attrs, slog.Attr{Key: f.name, Value: fieldValue(fv, depth+1)})
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// fieldValue converts a field value, recursing into nested structs and
+// into the structs held by slices, arrays and maps
+func fieldValue(fv reflect.Value, depth int) slog.Value {
+	if fv.Type().Implements(logValuerType) && fv.CanInterface() {
+		return slog.AnyValue(fv.Interface())
+	}
+
+	if isStructType(fv.Type()) {
+		return structValue(fv, depth)
+	}
+
+	switch fv.Kind() {
+	case reflect.Interface:
+		if !fv.IsNil() {
+			return fieldValue(fv.Elem(), depth)
+		}
+	case reflect.Slice, reflect.Array:
+		if fv.Kind() == reflect.Slice && fv.IsNil() || !holdsStructs(fv) {
+			break
+		}
+		if depth >= maxStructDepth {
+			return slog.StringValue(MaxDepthValue)
+		}
+		attrs := make([]slog.Attr, fv.Len())
+		for i := range attrs {
+			attrs[i] = slog.Attr{Key: strconv.Itoa(i), Value: fieldValue(fv.Index(i), depth+1)}
+		}
+		return slog.GroupValue(attrs...)
+	case reflect.Map:
+		if fv.IsNil() || !holdsStructs(fv) {
+			break
+		}
+		if depth >= maxStructDepth {
+			return slog.StringValue(MaxDepthValue)
+		}
+		attrs := make([]slog.Attr, 0, fv.Len())
+		iter := fv.MapRange()
+		for iter.Next() {
+			attrs = append(attrs, slog.Attr{
+				Key:   fmt.Sprint(iter.Key().Interface()),
+				Value: fieldValue(iter.Value(), depth+1),
+			})
+		}
+		slices.SortFunc(attrs, func(a, b slog.Attr) int { return strings.Compare(a.Key, b.Key) })
+		return slog.GroupValue(attrs...)
+	}
+
+	return slog.AnyValue(fv.Interface())
+}
+
+// isStructType reports whether t is a struct or a pointer to one, other than time.Time
+func isStructType(t reflect.Type) bool {
+	t = indirectType(t)
+	return t.Kind() == reflect.Struct && t != timeType
+}
+
+// holdsStructs reports whether the elements of the slice, array or map v
+// may be structs, either by type or, for interface elements, by value
+func holdsStructs(v reflect.Value) bool {
+	elem := v.Type().Elem()
+	if elem.Kind() != reflect.Interface {
+		for elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array || elem.Kind() == reflect.Map {
+			elem = elem.Elem()
+		}
+		return isStructType(elem)
+	}
+
+	if v.Kind() == reflect.Map {
+		iter := v.MapRange()
+		for iter.Next() {
+			if holdsStruct(iter.Value()) {
+				return true
+			}
+		}
+		return false
+	}
+	for i := range v.Len() {
+		if holdsStruct(v.Index(i)) {
+			return true
+		}
+	}
+	return false
+}
+
+// holdsStruct reports whether the interface value e holds a struct or a
+// container of structs
+func holdsStruct(e reflect.Value) bool {
+	if e.IsNil() {
+		return false
+	}
+	e = e.Elem()
+	switch e.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return holdsStructs(e)
+	default:
+		return isStructType(e.Type())
+	}
+}
+
+// structPlan returns the cached field plans for the struct type t
+func structPlan(t reflect.Type) []fieldPlan {
+	if plan, ok := structPlans.Load(t); ok {
+		return plan.([]fieldPlan)
+	}
+
+	plan := make([]fieldPlan, 0, t.NumField())
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		f := fieldPlan{index: i, name: field.Name}
+		skip := false
+		for _, opt := range strings.Split(field.Tag.Get(StructTag), ",") {
+			opt = strings.TrimSpace(opt)
+			switch {
+			case opt == "-":
+				skip = true
+			case opt == "redact":
+				f.redact = true
+			case opt == "omitempty":
+				f.omitEmpty = true
+			case strings.HasPrefix(opt, "name="):
+				f.name = strings.TrimPrefix(opt, "name=")
+			}
+		}
+		if skip {
+			continue
+		}
+
+		// Embedded structs without a name are inlined into the parent group
+		if field.Anonymous && f.name == field.Name && indirectType(field.Type).Kind() == reflect.Struct {
+			f.inline = true
+			f.name = ""
+		}
+
+		plan = append(plan, f)
+	}
+
+	actual, _ := structPlans.LoadOrStore(t, plan)
+	return actual.([]fieldPlan)
+}
+
+// indirectType returns the type pointed to by t, following all pointers
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
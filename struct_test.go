@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Audit struct {
+	CreatedBy string `log:"name=createdBy"`
+}
+
+type address struct {
+	City string
+	Zip  string `log:"omitempty"`
+}
+
+type user struct {
+	Audit
+	ID       int    `log:"name=userId"`
+	Email    string `log:"redact"`
+	Password string `log:"-"`
+	Nickname string `log:"omitempty"`
+	Address  *address
+	Joined   time.Time
+	internal string
+}
+
+func TestStruct(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	u := user{
+		Audit:    Audit{CreatedBy: "admin"},
+		ID:       7,
+		Email:    "jane@example.com",
+		Password: "hunter2",
+		Address:  &address{City: "Berlin"},
+		Joined:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		internal: "hidden",
+	}
+	log.Info("user", "user", Struct(u))
+
+	out := buf.String()
+	assert.Contains(t, out, `"user":{"createdBy":"admin","userId":7,"Email":"[REDACTED]","Address":{"City":"Berlin"},"Joined":"2024-01-02T03:04:05Z"}`)
+	assert.NotContains(t, out, "hunter2")
+	assert.NotContains(t, out, "hidden")
+	assert.NotContains(t, out, "Nickname")
+}
+
+func TestStructNonStruct(t *testing.T) {
+	assert.Equal(t, slog.KindInt64, Struct(42).LogValue().Kind())
+	assert.Nil(t, Struct((*user)(nil)).LogValue().Any())
+}
+
+type node struct {
+	Name string
+	Next *node
+}
+
+type secretNode struct {
+	Name   string
+	Secret string `log:"redact"`
+	Token  string `log:"-"`
+	Next   *secretNode
+}
+
+func TestStructCycle(t *testing.T) {
+	n := &node{Name: "loop"}
+	n.Next = n
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("cycle", "node", Struct(n))
+	assert.Contains(t, buf.String(), "node.Name=loop")
+	assert.Contains(t, buf.String(), MaxDepthValue)
+
+	s := &secretNode{Name: "loop", Secret: "s3cret", Token: "t0ken"}
+	s.Next = s
+
+	buf.Reset()
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("cycle", "node", Struct(s))
+	assert.Contains(t, buf.String(), `"Secret":"[REDACTED]"`)
+	assert.Contains(t, buf.String(), MaxDepthValue)
+	assert.NotContains(t, buf.String(), "s3cret")
+	assert.NotContains(t, buf.String(), "t0ken")
+}
+
+type account struct {
+	Owners   []user
+	ByEmail  map[string]*user
+	Pair     [1]user
+	Any      []any
+	Nested   [][]user
+	Tags     []string
+	NoOwners []user
+}
+
+func TestStructContainers(t *testing.T) {
+	u := user{ID: 1, Email: "jane@example.com", Password: "hunter2"}
+	a := account{
+		Owners:  []user{u},
+		ByEmail: map[string]*user{"jane": &u},
+		Pair:    [1]user{u},
+		Any:     []any{"plain", u},
+		Nested:  [][]user{{u}},
+		Tags:    []string{"a", "b"},
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("account", "account", Struct(a))
+
+	out := buf.String()
+	assert.NotContains(t, out, "jane@example.com")
+	assert.NotContains(t, out, "hunter2")
+	assert.Contains(t, out, `"Owners":{"0":{"createdBy":"","userId":1,"Email":"[REDACTED]"`)
+	assert.Contains(t, out, `"ByEmail":{"jane":{"createdBy":"","userId":1,"Email":"[REDACTED]"`)
+	assert.Contains(t, out, `"Pair":{"0":{"createdBy":"","userId":1,"Email":"[REDACTED]"`)
+	assert.Contains(t, out, `"Any":{"0":"plain","1":{"createdBy":"","userId":1,"Email":"[REDACTED]"`)
+	assert.Contains(t, out, `"Nested":{"0":{"0":{"createdBy":"","userId":1,"Email":"[REDACTED]"`)
+	assert.Contains(t, out, `"Tags":["a","b"]`)
+	assert.Contains(t, out, `"NoOwners":null`)
+
+	buf.Reset()
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("users", "users", Struct([]user{u}))
+	assert.NotContains(t, buf.String(), "hunter2")
+	assert.Contains(t, buf.String(), `"users":{"0":{`)
+}
+
+func TestStructPlanCache(t *testing.T) {
+	first := structPlan(reflect.TypeFor[user]())
+	second := structPlan(reflect.TypeFor[user]())
+	assert.Equal(t, &first[0], &second[0])
+}
+
+func TestColoredHandlerStruct(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(NewColoredHandler(&buf, nil, true))
+
+	log.Info("user", "user", Struct(user{ID: 1, Email: "jane@example.com"}))
+
+	out := buf.String()
+	assert.Contains(t, out, `"userId": 1`)
+	assert.NotContains(t, out, "jane@example.com")
+}
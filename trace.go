@@ -0,0 +1,245 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Trace attribute keys
+const (
+	TraceIDKey    = "trace_id"
+	SpanIDKey     = "span_id"
+	TraceFlagsKey = "trace_flags"
+)
+
+// TraceContext holds the W3C trace context identifiers of a span
+type TraceContext struct {
+	// TraceID is the 16-byte trace identifier
+	TraceID [16]byte
+
+	// SpanID is the 8-byte span identifier
+	SpanID [8]byte
+
+	// TraceFlags holds the W3C trace flags (bit 0 is "sampled")
+	TraceFlags byte
+}
+
+// TraceExtractor returns the trace context carried by ctx, if any
+type TraceExtractor func(ctx context.Context) (TraceContext, bool)
+
+var (
+	// traceExtractors holds the globally registered trace extractors
+	traceExtractors []TraceExtractor
+
+	// traceExtractorsMu protects traceExtractors
+	traceExtractorsMu sync.RWMutex
+)
+
+// traceContextKey is the context key for ContextWithTraceContext
+type traceContextKey struct{}
+
+// errInvalidTraceparent is returned for malformed traceparent headers
+var errInvalidTraceparent = errors.New("invalid traceparent")
+
+// RegisterTraceExtractor registers an extractor used by trace handlers created
+// without an explicit extractor. Integrations such as the otel subpackage call
+// it from init so the core package doesn't depend on them.
+func RegisterTraceExtractor(fn TraceExtractor) {
+	if fn == nil {
+		return
+	}
+
+	traceExtractorsMu.Lock()
+	defer traceExtractorsMu.Unlock()
+	traceExtractors = append(traceExtractors, fn)
+}
+
+// ContextWithTraceContext returns a copy of ctx carrying tc
+func ContextWithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, tc)
+}
+
+// TraceContextFromContext returns the trace context stored with ContextWithTraceContext
+func TraceContextFromContext(ctx context.Context) (TraceContext, bool) {
+	if ctx == nil {
+		return TraceContext{}, false
+	}
+
+	tc, ok := ctx.Value(traceContextKey{}).(TraceContext)
+	return tc, ok && tc.IsValid()
+}
+
+// ExtractTraceContext returns the trace context carried by ctx, trying the
+// registered extractors first and then the value set with ContextWithTraceContext
+func ExtractTraceContext(ctx context.Context) (TraceContext, bool) {
+	if ctx == nil {
+		return TraceContext{}, false
+	}
+
+	traceExtractorsMu.RLock()
+	fns := traceExtractors
+	traceExtractorsMu.RUnlock()
+
+	for _, fn := range fns {
+		if tc, ok := fn(ctx); ok && tc.IsValid() {
+			return tc, true
+		}
+	}
+
+	return TraceContextFromContext(ctx)
+}
+
+// ParseTraceparent parses a W3C traceparent header value
+func ParseTraceparent(s string) (TraceContext, error) {
+	var tc TraceContext
+
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return tc, fmt.Errorf("%w: %q", errInvalidTraceparent, s)
+	}
+
+	// Version 00 has exactly four fields, future versions may append more
+	if parts[0] == "00" && len(parts) != 4 {
+		return tc, fmt.Errorf("%w: %q", errInvalidTraceparent, s)
+	}
+
+	var flags [1]byte
+	if !decodeHex(tc.TraceID[:], parts[1]) ||
+		!decodeHex(tc.SpanID[:], parts[2]) ||
+		!decodeHex(flags[:], parts[3]) {
+		return tc, fmt.Errorf("%w: %q", errInvalidTraceparent, s)
+	}
+	tc.TraceFlags = flags[0]
+
+	if !tc.IsValid() {
+		return tc, fmt.Errorf("%w: %q", errInvalidTraceparent, s)
+	}
+
+	return tc, nil
+}
+
+// ServerTraceContext returns the trace context of a server span handling a
+// request with the given traceparent header value. A valid traceparent is
+// continued in a new span, and parentSpanID is the span ID of the caller.
+// Otherwise a new trace, not sampled, is started and parentSpanID is empty.
+func ServerTraceContext(traceparent string) (tc TraceContext, parentSpanID string) {
+	tc, err := ParseTraceparent(traceparent)
+	if err == nil {
+		parentSpanID = tc.SpanIDString()
+	} else {
+		tc = TraceContext{}
+		_, _ = rand.Read(tc.TraceID[:])
+	}
+	_, _ = rand.Read(tc.SpanID[:])
+
+	return tc, parentSpanID
+}
+
+// decodeHex decodes lowercase hex s into dst, which it must fill exactly
+func decodeHex(dst []byte, s string) bool {
+	if len(s) != hex.EncodedLen(len(dst)) || strings.ToLower(s) != s {
+		return false
+	}
+
+	_, err := hex.Decode(dst, []byte(s))
+	return err == nil
+}
+
+// IsValid reports whether both the trace and span IDs are non-zero
+func (tc TraceContext) IsValid() bool {
+	return tc.TraceID != [16]byte{} && tc.SpanID != [8]byte{}
+}
+
+// IsSampled reports whether the sampled flag is set
+func (tc TraceContext) IsSampled() bool {
+	return tc.TraceFlags&0x01 != 0
+}
+
+// TraceIDString returns the hex encoded trace ID
+func (tc TraceContext) TraceIDString() string {
+	return hex.EncodeToString(tc.TraceID[:])
+}
+
+// SpanIDString returns the hex encoded span ID
+func (tc TraceContext) SpanIDString() string {
+	return hex.EncodeToString(tc.SpanID[:])
+}
+
+// TraceFlagsString returns the hex encoded trace flags
+func (tc TraceContext) TraceFlagsString() string {
+	return hex.EncodeToString([]byte{tc.TraceFlags})
+}
+
+// Traceparent returns the W3C traceparent header value
+func (tc TraceContext) Traceparent() string {
+	return "00-" + tc.TraceIDString() + "-" + tc.SpanIDString() + "-" + tc.TraceFlagsString()
+}
+
+// Attrs returns the trace_id, span_id and trace_flags attributes
+func (tc TraceContext) Attrs() []slog.Attr {
+	return []slog.Attr{
+		slog.String(TraceIDKey, tc.TraceIDString()),
+		slog.String(SpanIDKey, tc.SpanIDString()),
+		slog.String(TraceFlagsKey, tc.TraceFlagsString()),
+	}
+}
+
+// TraceHandler is a slog.Handler that adds trace_id, span_id and trace_flags
+// to records logged with a context carrying a trace context.
+type TraceHandler struct {
+	next    slog.Handler
+	extract TraceExtractor
+}
+
+// NewTraceHandler creates a new TraceHandler wrapping next.
+// If extract is nil, ExtractTraceContext is used.
+func NewTraceHandler(next slog.Handler, extract TraceExtractor) *TraceHandler {
+	if extract == nil {
+		extract = ExtractTraceContext
+	}
+	return &TraceHandler{
+		next:    next,
+		extract: extract,
+	}
+}
+
+// Enabled implements slog.Handler.
+func (h *TraceHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+//
+//nolint:gocritic // Cannot change signature due to interface contract
+func (h *TraceHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		if tc, ok := h.extract(ctx); ok && tc.IsValid() {
+			r = r.Clone()
+			r.AddAttrs(tc.Attrs()...)
+		}
+	}
+
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *TraceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &TraceHandler{
+		next:    h.next.WithAttrs(attrs),
+		extract: h.extract,
+	}
+}
+
+// WithGroup implements slog.Handler.
+func (h *TraceHandler) WithGroup(name string) slog.Handler {
+	return &TraceHandler{
+		next:    h.next.WithGroup(name),
+		extract: h.extract,
+	}
+}
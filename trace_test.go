@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		wantErr bool
+	}{
+		{"valid", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"future version with extra field", "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false},
+		{"invalid version", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+		{"extra field on version 00", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", true},
+		{"zero trace id", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", true},
+		{"uppercase hex", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", true},
+		{"short span id", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa-01", true},
+		{"empty", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc, err := ParseTraceparent(tt.header)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceIDString())
+			assert.Equal(t, "00f067aa0ba902b7", tc.SpanIDString())
+			assert.True(t, tc.IsSampled())
+		})
+	}
+}
+
+func TestServerTraceContext(t *testing.T) {
+	tc, parent := ServerTraceContext("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceIDString())
+	assert.NotEqual(t, "00f067aa0ba902b7", tc.SpanIDString())
+	assert.Equal(t, "00f067aa0ba902b7", parent)
+	assert.True(t, tc.IsSampled())
+
+	// Without a valid traceparent a new trace is started
+	tc, parent = ServerTraceContext("")
+	assert.True(t, tc.IsValid())
+	assert.False(t, tc.IsSampled())
+	assert.Empty(t, parent)
+}
+
+func TestTraceHandler(t *testing.T) {
+	tc, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	require.NoError(t, err)
+
+	mock := &MockLogger{}
+	log := slog.New(NewTraceHandler(mock, nil))
+
+	log.InfoContext(ContextWithTraceContext(context.Background(), tc), "traced")
+	log.InfoContext(context.Background(), "untraced")
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 2)
+	assert.Equal(t, []slog.Attr{
+		slog.String(TraceIDKey, "4bf92f3577b34da6a3ce929d0e0e4736"),
+		slog.String(SpanIDKey, "00f067aa0ba902b7"),
+		slog.String(TraceFlagsKey, "01"),
+	}, logs[0].Attrs)
+	assert.Empty(t, logs[1].Attrs)
+}
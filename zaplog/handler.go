@@ -0,0 +1,243 @@
+package zaplog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+
+	"github.com/legrch/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// HandlerOptions configures a Handler
+type HandlerOptions struct {
+	// Level is the minimum enabled logging level, in addition to the level of the core
+	Level slog.Leveler
+
+	// AddSource sets the caller of entries, which the core encodes if its
+	// encoder has a caller key
+	AddSource bool
+}
+
+// Handler is a slog.Handler that writes records to a zapcore.Core. Groups
+// become zap namespaces and a LoggerNameKey attribute added with WithAttrs
+// outside groups becomes the logger name.
+type Handler struct {
+	core      zapcore.Core
+	level     slog.Leveler
+	addSource bool
+	name      string
+
+	// groups are the groups opened since the last attributes, added as
+	// namespaces once the group has attributes
+	groups []string
+}
+
+// NewHandler creates a Handler writing to core
+func NewHandler(core zapcore.Core, opts *HandlerOptions) *Handler {
+	if opts == nil {
+		opts = &HandlerOptions{}
+	}
+	return &Handler{core: core, level: opts.Level, addSource: opts.AddSource}
+}
+
+// Backend returns a logger.SinkFactory for Config.Backend, writing records
+// to core with the level and source settings of the config
+func Backend(core zapcore.Core) logger.SinkFactory {
+	return func(_ *logger.Config, opts *slog.HandlerOptions) (slog.Handler, error) {
+		return NewHandler(core, &HandlerOptions{Level: opts.Level, AddSource: opts.AddSource}), nil
+	}
+}
+
+// ZapLevel converts a slog level to a zap level
+func ZapLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return zapcore.DebugLevel
+	case level < slog.LevelWarn:
+		return zapcore.InfoLevel
+	case level < slog.LevelError:
+		return zapcore.WarnLevel
+	case level < logger.LevelPanic:
+		return zapcore.ErrorLevel
+	case level < logger.LevelFatal:
+		return zapcore.PanicLevel
+	default:
+		return zapcore.FatalLevel
+	}
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	if h.level != nil && level < h.level.Level() {
+		return false
+	}
+	return h.core.Enabled(ZapLevel(level))
+}
+
+// Handle implements slog.Handler.
+//
+//nolint:gocritic // Cannot change signature due to interface contract
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	ent := zapcore.Entry{
+		LoggerName: h.name,
+		Time:       r.Time,
+		Level:      ZapLevel(r.Level),
+		Message:    r.Message,
+	}
+	if h.addSource && r.PC != 0 {
+		f, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		ent.Caller = zapcore.EntryCaller{Defined: true, PC: r.PC, File: f.File, Line: f.Line, Function: f.Function}
+	}
+
+	// Records replayed by the flight recorder are below the level of the core
+	ce := h.core.Check(ent, nil)
+	backfill := ce == nil && logger.IsBackfill(ctx)
+	if ce == nil && !backfill {
+		return nil
+	}
+
+	fields := make([]zapcore.Field, 0, len(h.groups)+r.NumAttrs())
+	r.Attrs(func(attr slog.Attr) bool {
+		fields = appendField(fields, attr)
+		return true
+	})
+	if len(fields) > 0 {
+		fields = append(namespaces(h.groups), fields...)
+	}
+
+	if backfill {
+		if err := h.core.Write(ent, fields); err != nil {
+			return fmt.Errorf("zaplog: write entry: %w", err)
+		}
+		return nil
+	}
+
+	// The checked entry has no exit or panic action, the record is only written
+	ce.Write(fields...)
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+
+	fields := make([]zapcore.Field, 0, len(attrs))
+	for _, attr := range attrs {
+		if attr.Key == logger.LoggerNameKey && len(h.groups) == 0 {
+			h2.name = joinName(h2.name, attr.Value.String())
+			continue
+		}
+		fields = appendField(fields, attr)
+	}
+	if len(fields) == 0 {
+		return &h2
+	}
+
+	h2.core = h.core.With(append(namespaces(h.groups), fields...))
+	h2.groups = nil
+	return &h2
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &h2
+}
+
+// Flush syncs the core
+func (h *Handler) Flush(context.Context) error {
+	if err := h.core.Sync(); err != nil {
+		return fmt.Errorf("zaplog: sync core: %w", err)
+	}
+	return nil
+}
+
+// joinName appends name to the logger name like zap.Logger.Named
+func joinName(base, name string) string {
+	if base == "" {
+		return name
+	}
+	return base + "." + name
+}
+
+// namespaces returns a namespace field for each group
+func namespaces(groups []string) []zapcore.Field {
+	fields := make([]zapcore.Field, len(groups))
+	for i, group := range groups {
+		fields[i] = zap.Namespace(group)
+	}
+	return fields
+}
+
+// appendField appends attr converted to a zap field, ignoring empty
+// attributes and inlining groups without a key like slog handlers
+func appendField(fields []zapcore.Field, attr slog.Attr) []zapcore.Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		group := attr.Value.Group()
+		if len(group) == 0 {
+			return fields
+		}
+		if attr.Key == "" {
+			for _, a := range group {
+				fields = appendField(fields, a)
+			}
+			return fields
+		}
+		return append(fields, zap.Object(attr.Key, groupMarshaler(group)))
+	}
+
+	return append(fields, attrToField(attr))
+}
+
+// attrToField converts an attribute with a resolved value other than a group
+func attrToField(attr slog.Attr) zapcore.Field {
+	v := attr.Value
+	switch v.Kind() {
+	case slog.KindString:
+		return zap.String(attr.Key, v.String())
+	case slog.KindInt64:
+		return zap.Int64(attr.Key, v.Int64())
+	case slog.KindUint64:
+		return zap.Uint64(attr.Key, v.Uint64())
+	case slog.KindFloat64:
+		return zap.Float64(attr.Key, v.Float64())
+	case slog.KindBool:
+		return zap.Bool(attr.Key, v.Bool())
+	case slog.KindDuration:
+		return zap.Duration(attr.Key, v.Duration())
+	case slog.KindTime:
+		return zap.Time(attr.Key, v.Time())
+	default:
+		if err, ok := v.Any().(error); ok {
+			return zap.NamedError(attr.Key, err)
+		}
+		return zap.Any(attr.Key, v.Any())
+	}
+}
+
+// groupMarshaler encodes the attributes of a group as a zap object
+type groupMarshaler []slog.Attr
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (g groupMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	var fields []zapcore.Field
+	for _, attr := range g {
+		fields = appendField(fields, attr)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return nil
+}
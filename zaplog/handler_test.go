@@ -0,0 +1,114 @@
+package zaplog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/legrch/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestHandler(t *testing.T) {
+	core, observed := observer.New(zapcore.DebugLevel)
+	log := slog.New(NewHandler(core, &HandlerOptions{AddSource: true}))
+
+	log.With(logger.LoggerNameKey, "db").
+		With("service", "api").
+		WithGroup("req").
+		With("id", "r1").
+		Warn("slow", "took_ms", 120, slog.Group("user", "name", "alice"), "err", errors.New("timeout"))
+
+	entries := observed.AllUntimed()
+	require.Len(t, entries, 1)
+	e := entries[0]
+	assert.Equal(t, zapcore.WarnLevel, e.Level)
+	assert.Equal(t, "slow", e.Message)
+	assert.Equal(t, "db", e.LoggerName)
+	assert.True(t, e.Caller.Defined)
+	assert.Equal(t, "github.com/legrch/logger/zaplog.TestHandler", e.Caller.Function)
+
+	assert.Equal(t, map[string]any{
+		"service": "api",
+		"req": map[string]any{
+			"id":      "r1",
+			"took_ms": int64(120),
+			"user":    map[string]any{"name": "alice"},
+			"err":     "timeout",
+		},
+	}, e.ContextMap())
+}
+
+func TestHandlerEmptyGroup(t *testing.T) {
+	core, observed := observer.New(zapcore.DebugLevel)
+	slog.New(NewHandler(core, nil)).With("a", 1).WithGroup("empty").Info("no attrs")
+
+	entries := observed.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, map[string]any{"a": int64(1)}, entries[0].ContextMap())
+	assert.False(t, entries[0].Caller.Defined)
+}
+
+func TestHandlerLevels(t *testing.T) {
+	core, observed := observer.New(zapcore.InfoLevel)
+	h := NewHandler(core, &HandlerOptions{Level: slog.LevelWarn})
+
+	assert.False(t, h.Enabled(context.Background(), slog.LevelDebug))
+	assert.False(t, h.Enabled(context.Background(), slog.LevelInfo))
+	assert.True(t, h.Enabled(context.Background(), slog.LevelWarn))
+
+	log := slog.New(h)
+	log.Log(context.Background(), logger.LevelPanic, "panicked")
+	log.Log(context.Background(), logger.LevelFatal, "fatal")
+
+	entries := observed.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.Equal(t, zapcore.PanicLevel, entries[0].Level)
+	assert.Equal(t, zapcore.FatalLevel, entries[1].Level)
+
+	assert.Equal(t, zapcore.DebugLevel, ZapLevel(logger.LevelTrace))
+}
+
+func TestHandlerBackfill(t *testing.T) {
+	core, observed := observer.New(zapcore.InfoLevel)
+	log := slog.New(logger.NewFlightRecorderHandler(NewHandler(core, nil), nil))
+
+	log.Debug("buffered")
+	log.Error("failed")
+
+	entries := observed.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "buffered", entries[0].Message)
+	assert.Equal(t, zapcore.DebugLevel, entries[0].Level)
+	assert.Equal(t, "failed", entries[1].Message)
+}
+
+func TestBackend(t *testing.T) {
+	var buf bytes.Buffer
+	enc := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	core := zapcore.NewCore(enc, zapcore.AddSync(&buf), zapcore.DebugLevel)
+
+	log, err := logger.Open(&logger.Config{
+		Level:           "info",
+		EnableCaller:    true,
+		EnableRedaction: true,
+		Backend:         Backend(core),
+	})
+	require.NoError(t, err)
+
+	log.Debug("hidden")
+	log.Info("login", "password", "hunter2")
+	require.NoError(t, log.Flush(context.Background()))
+
+	out := buf.String()
+	assert.NotContains(t, out, "hidden")
+	assert.Contains(t, out, `"msg":"login"`)
+	assert.NotContains(t, out, "hunter2")
+	assert.Contains(t, out, `"caller":"zaplog/handler_test.go:`)
+}
@@ -0,0 +1,212 @@
+// Package zaplog provides a zapcore.Core that writes zap entries to a
+// slog.Handler, so code using *zap.Logger logs through the handler chain
+// configured by logger.New or logger.Open:
+//
+//	log, _ := logger.Open(cfg)
+//	zl := zaplog.New(log.Handler(), &zaplog.Options{Flusher: log})
+//	zl.Info("connected", zap.String("addr", addr))
+package zaplog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/legrch/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// StacktraceKey is the attribute holding the stack captured by zap.AddStacktrace
+const StacktraceKey = "stacktrace"
+
+// Options configures a Core
+type Options struct {
+	// Flusher is flushed by Sync, typically the *logger.Logger returned by Open
+	Flusher logger.Flusher
+}
+
+// Core is a zapcore.Core backed by a slog.Handler
+type Core struct {
+	handler slog.Handler
+	flusher logger.Flusher
+}
+
+// NewCore creates a Core writing to h
+func NewCore(h slog.Handler, opts *Options) *Core {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return &Core{handler: h, flusher: opts.Flusher}
+}
+
+// New creates a *zap.Logger writing to h. The caller is recorded so that
+// handlers adding the source report the zap call site.
+func New(h slog.Handler, opts *Options, zapOpts ...zap.Option) *zap.Logger {
+	return zap.New(NewCore(h, opts), append([]zap.Option{zap.AddCaller()}, zapOpts...)...)
+}
+
+// Level converts a zap level to a slog level
+func Level(level zapcore.Level) slog.Level {
+	switch {
+	case level < zapcore.DebugLevel:
+		return logger.LevelTrace
+	case level == zapcore.DebugLevel:
+		return slog.LevelDebug
+	case level == zapcore.InfoLevel:
+		return slog.LevelInfo
+	case level == zapcore.WarnLevel:
+		return slog.LevelWarn
+	case level <= zapcore.DPanicLevel:
+		return slog.LevelError
+	case level == zapcore.PanicLevel:
+		return logger.LevelPanic
+	default:
+		return logger.LevelFatal
+	}
+}
+
+// Enabled implements zapcore.LevelEnabler.
+func (c *Core) Enabled(level zapcore.Level) bool {
+	return c.handler.Enabled(context.Background(), Level(level))
+}
+
+// With implements zapcore.Core. A namespace opens a group for the
+// remaining fields and for the fields of later calls.
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	h := c.handler
+	var attrs []slog.Attr
+	for _, f := range fields {
+		if f.Type == zapcore.NamespaceType {
+			if len(attrs) > 0 {
+				h = h.WithAttrs(attrs)
+				attrs = nil
+			}
+			h = h.WithGroup(f.Key)
+			continue
+		}
+		if attr, ok := fieldToAttr(f); ok {
+			attrs = append(attrs, attr)
+		}
+	}
+	if len(attrs) > 0 {
+		h = h.WithAttrs(attrs)
+	}
+
+	return &Core{handler: h, flusher: c.flusher}
+}
+
+// Check implements zapcore.Core.
+func (c *Core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *Core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	r := slog.NewRecord(ent.Time, Level(ent.Level), ent.Message, ent.Caller.PC)
+	if ent.LoggerName != "" {
+		r.AddAttrs(slog.String(logger.LoggerNameKey, ent.LoggerName))
+	}
+	r.AddAttrs(fieldsToAttrs(fields)...)
+	if ent.Stack != "" {
+		r.AddAttrs(slog.String(StacktraceKey, ent.Stack))
+	}
+
+	if err := c.handler.Handle(context.Background(), r); err != nil {
+		return fmt.Errorf("zaplog: handle entry: %w", err)
+	}
+
+	// zap exits or panics after writing these, so flush like its own cores do
+	if ent.Level > zapcore.ErrorLevel {
+		return c.Sync()
+	}
+	return nil
+}
+
+// Sync implements zapcore.Core.
+func (c *Core) Sync() error {
+	if c.flusher == nil {
+		return nil
+	}
+	if err := c.flusher.Flush(context.Background()); err != nil {
+		return fmt.Errorf("zaplog: flush: %w", err)
+	}
+	return nil
+}
+
+// fieldsToAttrs converts fields to attributes, nesting the fields after a
+// namespace in a group
+func fieldsToAttrs(fields []zapcore.Field) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for i, f := range fields {
+		if f.Type == zapcore.NamespaceType {
+			rest := fieldsToAttrs(fields[i+1:])
+			if len(rest) > 0 {
+				attrs = append(attrs, slog.Attr{Key: f.Key, Value: slog.GroupValue(rest...)})
+			}
+			break
+		}
+		if attr, ok := fieldToAttr(f); ok {
+			attrs = append(attrs, attr)
+		}
+	}
+	return attrs
+}
+
+// fieldToAttr converts a field other than a namespace to an attribute
+func fieldToAttr(f zapcore.Field) (slog.Attr, bool) {
+	switch f.Type {
+	case zapcore.SkipType:
+		return slog.Attr{}, false
+	case zapcore.StringType:
+		return slog.String(f.Key, f.String), true
+	case zapcore.BoolType:
+		return slog.Bool(f.Key, f.Integer == 1), true
+	case zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type:
+		return slog.Int64(f.Key, f.Integer), true
+	case zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type, zapcore.UintptrType:
+		return slog.Uint64(f.Key, uint64(f.Integer)), true
+	case zapcore.Float64Type:
+		return slog.Float64(f.Key, math.Float64frombits(uint64(f.Integer))), true
+	case zapcore.Float32Type:
+		return slog.Float64(f.Key, float64(math.Float32frombits(uint32(f.Integer)))), true
+	case zapcore.DurationType:
+		return slog.Duration(f.Key, time.Duration(f.Integer)), true
+	case zapcore.TimeType:
+		t := time.Unix(0, f.Integer)
+		if loc, ok := f.Interface.(*time.Location); ok {
+			t = t.In(loc)
+		}
+		return slog.Time(f.Key, t), true
+	case zapcore.TimeFullType:
+		t, _ := f.Interface.(time.Time)
+		return slog.Time(f.Key, t), true
+	case zapcore.ErrorType:
+		err, ok := f.Interface.(error)
+		if !ok || err == nil {
+			return slog.Attr{}, false
+		}
+		return slog.Attr{Key: f.Key, Value: logger.ErrValue(err)}, true
+	case zapcore.StringerType:
+		if s, ok := f.Interface.(fmt.Stringer); ok {
+			return slog.String(f.Key, s.String()), true
+		}
+		return slog.Any(f.Key, f.Interface), true
+	case zapcore.ReflectType:
+		return slog.Any(f.Key, f.Interface), true
+	default:
+		// Objects, arrays, binary and complex values are encoded by zap
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		v, ok := enc.Fields[f.Key]
+		if !ok {
+			return slog.Attr{}, false
+		}
+		return slog.Any(f.Key, v), true
+	}
+}
@@ -0,0 +1,132 @@
+package zaplog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/legrch/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func decode(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var m map[string]any
+		require.NoError(t, dec.Decode(&m))
+		records = append(records, m)
+	}
+	return records
+}
+
+// flushCounter counts Flush calls
+type flushCounter struct{ n int }
+
+func (f *flushCounter) Flush(context.Context) error {
+	f.n++
+	return nil
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	h := slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true})
+	zl := New(h, nil).Named("db")
+
+	zl.Info("connected",
+		zap.String("addr", "localhost:5432"),
+		zap.Int("pool", 4),
+		zap.Bool("tls", true),
+		zap.Float64("ratio", 0.5),
+		zap.Duration("took", time.Second),
+		zap.Strings("tags", []string{"a", "b"}),
+		zap.Error(nil),
+	)
+
+	records := decode(t, &buf)
+	require.Len(t, records, 1)
+	r := records[0]
+	assert.Equal(t, "INFO", r["level"])
+	assert.Equal(t, "connected", r["msg"])
+	assert.Equal(t, "db", r[logger.LoggerNameKey])
+	assert.Equal(t, "localhost:5432", r["addr"])
+	assert.InDelta(t, 4, r["pool"], 0)
+	assert.Equal(t, true, r["tls"])
+	assert.InDelta(t, 0.5, r["ratio"], 0)
+	assert.InDelta(t, float64(time.Second), r["took"], 0)
+	assert.Equal(t, []any{"a", "b"}, r["tags"])
+	assert.NotContains(t, r, "error")
+
+	source, ok := r["source"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "github.com/legrch/logger/zaplog.TestLogger", source["function"])
+}
+
+func TestLevels(t *testing.T) {
+	var buf bytes.Buffer
+	h := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn})
+	zl := New(h, nil)
+
+	assert.False(t, zl.Core().Enabled(zapcore.InfoLevel))
+	assert.True(t, zl.Core().Enabled(zapcore.WarnLevel))
+
+	zl.Info("hidden")
+	zl.Warn("shown")
+	assert.Len(t, decode(t, &buf), 1)
+
+	assert.Equal(t, logger.LevelTrace, Level(zapcore.DebugLevel-1))
+	assert.Equal(t, slog.LevelError, Level(zapcore.DPanicLevel))
+	assert.Equal(t, logger.LevelPanic, Level(zapcore.PanicLevel))
+	assert.Equal(t, logger.LevelFatal, Level(zapcore.FatalLevel))
+}
+
+func TestWithAndNamespace(t *testing.T) {
+	var buf bytes.Buffer
+	zl := New(slog.NewJSONHandler(&buf, nil), nil)
+
+	zl.With(zap.String("service", "api"), zap.Namespace("req"), zap.String("id", "r1")).
+		Info("handled", zap.Int("status", 200), zap.Namespace("user"), zap.String("name", "alice"))
+
+	records := decode(t, &buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "api", records[0]["service"])
+	assert.Equal(t, map[string]any{
+		"id":     "r1",
+		"status": float64(200),
+		"user":   map[string]any{"name": "alice"},
+	}, records[0]["req"])
+}
+
+func TestError(t *testing.T) {
+	mock := &logger.MockLogger{}
+	zl := New(mock, nil)
+
+	zl.Error("failed", zap.Error(errors.New("boom")), zap.NamedError("cause", errors.New("io")))
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 1)
+	require.Len(t, logs[0].Attrs, 2)
+	assert.Equal(t, logger.ErrorKey, logs[0].Attrs[0].Key)
+	assert.Equal(t, logger.ErrValue(errors.New("boom")).Resolve().String(), logs[0].Attrs[0].Value.Resolve().String())
+	assert.Equal(t, "cause", logs[0].Attrs[1].Key)
+}
+
+func TestSync(t *testing.T) {
+	flusher := &flushCounter{}
+	zl := New(&logger.MockLogger{}, &Options{Flusher: flusher})
+
+	require.NoError(t, zl.Sync())
+	assert.Equal(t, 1, flusher.n)
+
+	assert.Panics(t, func() { zl.Panic("bad") })
+	assert.Equal(t, 2, flusher.n)
+}
@@ -0,0 +1,388 @@
+// Package zerologlog provides a zerolog-style fluent event API that writes
+// to a slog.Handler, so code written against zerolog's chained calls logs
+// through the handler chain configured by logger.New or logger.Open:
+//
+//	log, _ := logger.Open(cfg)
+//	zl := zerologlog.New(log.Handler(), &zerologlog.Options{Flusher: log})
+//	zl.Info().Str("addr", addr).Int("pool", 4).Msg("connected")
+//
+// Events of disabled levels are nil and all their methods are no-ops, so
+// the fields of filtered records are never built.
+package zerologlog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/legrch/logger"
+)
+
+// exit terminates the program after a fatal event, replaced in tests
+var exit = os.Exit
+
+// Options configures a Logger
+type Options struct {
+	// Flusher is flushed before a fatal event exits or a panic event
+	// panics, typically the *logger.Logger returned by Open
+	Flusher logger.Flusher
+}
+
+// Logger creates events written to a slog.Handler. The zero value discards
+// all events.
+type Logger struct {
+	handler slog.Handler
+	flusher logger.Flusher
+}
+
+// New creates a Logger writing to h
+func New(h slog.Handler, opts *Options) Logger {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return Logger{handler: h, flusher: opts.Flusher}
+}
+
+// Handler returns the handler the logger writes to
+func (l Logger) Handler() slog.Handler {
+	return l.handler
+}
+
+// With returns a Context to build a child logger with fields
+func (l Logger) With() Context {
+	return Context{logger: l}
+}
+
+// Trace starts an event at logger.LevelTrace
+func (l Logger) Trace() *Event {
+	return l.newEvent(logger.LevelTrace)
+}
+
+// Debug starts an event at slog.LevelDebug
+func (l Logger) Debug() *Event {
+	return l.newEvent(slog.LevelDebug)
+}
+
+// Info starts an event at slog.LevelInfo
+func (l Logger) Info() *Event {
+	return l.newEvent(slog.LevelInfo)
+}
+
+// Warn starts an event at slog.LevelWarn
+func (l Logger) Warn() *Event {
+	return l.newEvent(slog.LevelWarn)
+}
+
+// Error starts an event at slog.LevelError
+func (l Logger) Error() *Event {
+	return l.newEvent(slog.LevelError)
+}
+
+// Err starts an event with err at slog.LevelError, or at slog.LevelInfo if err is nil
+func (l Logger) Err(err error) *Event {
+	if err == nil {
+		return l.Info()
+	}
+	return l.Error().Err(err)
+}
+
+// Fatal starts an event at logger.LevelFatal. Sending it flushes and exits with status 1.
+func (l Logger) Fatal() *Event {
+	return l.newEvent(logger.LevelFatal)
+}
+
+// Panic starts an event at logger.LevelPanic. Sending it flushes and panics with the message.
+func (l Logger) Panic() *Event {
+	return l.newEvent(logger.LevelPanic)
+}
+
+// WithLevel starts an event at level
+func (l Logger) WithLevel(level slog.Level) *Event {
+	return l.newEvent(level)
+}
+
+// newEvent returns an event at level, or nil if the level is disabled.
+// Fatal and panic events are always returned so that they exit or panic.
+func (l Logger) newEvent(level slog.Level) *Event {
+	enabled := l.handler != nil && l.handler.Enabled(context.Background(), level)
+	if !enabled && level < logger.LevelPanic {
+		return nil
+	}
+	return &Event{logger: l, level: level, enabled: enabled}
+}
+
+// Context builds a child Logger with fields added to all its events
+type Context struct {
+	logger Logger
+	attrs  []slog.Attr
+}
+
+// Logger returns the child logger
+func (c Context) Logger() Logger {
+	if c.logger.handler == nil || len(c.attrs) == 0 {
+		return c.logger
+	}
+	return Logger{handler: c.logger.handler.WithAttrs(c.attrs), flusher: c.logger.flusher}
+}
+
+// Str adds a string field
+func (c Context) Str(key, val string) Context {
+	return c.add(slog.String(key, val))
+}
+
+// Int adds an int field
+func (c Context) Int(key string, val int) Context {
+	return c.add(slog.Int(key, val))
+}
+
+// Int64 adds an int64 field
+func (c Context) Int64(key string, val int64) Context {
+	return c.add(slog.Int64(key, val))
+}
+
+// Bool adds a bool field
+func (c Context) Bool(key string, val bool) Context {
+	return c.add(slog.Bool(key, val))
+}
+
+// Float64 adds a float64 field
+func (c Context) Float64(key string, val float64) Context {
+	return c.add(slog.Float64(key, val))
+}
+
+// Dur adds a duration field
+func (c Context) Dur(key string, val time.Duration) Context {
+	return c.add(slog.Duration(key, val))
+}
+
+// Time adds a time field
+func (c Context) Time(key string, val time.Time) Context {
+	return c.add(slog.Time(key, val))
+}
+
+// Err adds err under logger.ErrorKey like logger.Err, unless it is nil
+func (c Context) Err(err error) Context {
+	return c.AnErr(logger.ErrorKey, err)
+}
+
+// AnErr adds err under key like logger.Err, unless it is nil
+func (c Context) AnErr(key string, err error) Context {
+	if err == nil {
+		return c
+	}
+	return c.add(slog.Attr{Key: key, Value: logger.ErrValue(err)})
+}
+
+// Interface adds a field of any type
+func (c Context) Interface(key string, val any) Context {
+	return c.add(slog.Any(key, val))
+}
+
+// Stringer adds the String of val
+func (c Context) Stringer(key string, val fmt.Stringer) Context {
+	return c.add(stringerAttr(key, val))
+}
+
+// add returns the context with attr appended, copying the fields so that
+// contexts built from a common base do not share them
+func (c Context) add(attr slog.Attr) Context {
+	attrs := make([]slog.Attr, len(c.attrs), len(c.attrs)+1)
+	copy(attrs, c.attrs)
+	c.attrs = append(attrs, attr)
+	return c
+}
+
+// Event is a record being built. A nil Event is disabled.
+type Event struct {
+	logger  Logger
+	level   slog.Level
+	enabled bool
+	ctx     context.Context
+	attrs   []slog.Attr
+}
+
+// Dict starts a set of fields to add as a group with Event.Dict
+func Dict() *Event {
+	return &Event{}
+}
+
+// Enabled reports whether the event will be written
+func (e *Event) Enabled() bool {
+	return e != nil && e.enabled
+}
+
+// Discard disables the event
+func (e *Event) Discard() *Event {
+	if e == nil {
+		return nil
+	}
+	e.enabled = false
+	return e
+}
+
+// Ctx sets the context the event is handled with, for handlers reading
+// attributes or the trace from it
+func (e *Event) Ctx(ctx context.Context) *Event {
+	if e == nil {
+		return nil
+	}
+	e.ctx = ctx
+	return e
+}
+
+// Str adds a string field
+func (e *Event) Str(key, val string) *Event {
+	return e.add(slog.String(key, val))
+}
+
+// Strs adds a string slice field
+func (e *Event) Strs(key string, vals []string) *Event {
+	return e.add(slog.Any(key, vals))
+}
+
+// Int adds an int field
+func (e *Event) Int(key string, val int) *Event {
+	return e.add(slog.Int(key, val))
+}
+
+// Int64 adds an int64 field
+func (e *Event) Int64(key string, val int64) *Event {
+	return e.add(slog.Int64(key, val))
+}
+
+// Uint64 adds a uint64 field
+func (e *Event) Uint64(key string, val uint64) *Event {
+	return e.add(slog.Uint64(key, val))
+}
+
+// Float64 adds a float64 field
+func (e *Event) Float64(key string, val float64) *Event {
+	return e.add(slog.Float64(key, val))
+}
+
+// Bool adds a bool field
+func (e *Event) Bool(key string, val bool) *Event {
+	return e.add(slog.Bool(key, val))
+}
+
+// Dur adds a duration field
+func (e *Event) Dur(key string, val time.Duration) *Event {
+	return e.add(slog.Duration(key, val))
+}
+
+// Time adds a time field
+func (e *Event) Time(key string, val time.Time) *Event {
+	return e.add(slog.Time(key, val))
+}
+
+// Err adds err under logger.ErrorKey like logger.Err, unless it is nil
+func (e *Event) Err(err error) *Event {
+	return e.AnErr(logger.ErrorKey, err)
+}
+
+// AnErr adds err under key like logger.Err, unless it is nil
+func (e *Event) AnErr(key string, err error) *Event {
+	if err == nil {
+		return e
+	}
+	return e.add(slog.Attr{Key: key, Value: logger.ErrValue(err)})
+}
+
+// Interface adds a field of any type
+func (e *Event) Interface(key string, val any) *Event {
+	return e.add(slog.Any(key, val))
+}
+
+// Any adds a field of any type
+func (e *Event) Any(key string, val any) *Event {
+	return e.add(slog.Any(key, val))
+}
+
+// Stringer adds the String of val
+func (e *Event) Stringer(key string, val fmt.Stringer) *Event {
+	return e.add(stringerAttr(key, val))
+}
+
+// Dict adds the fields of dict, created with Dict, as a group
+func (e *Event) Dict(key string, dict *Event) *Event {
+	if dict == nil {
+		return e
+	}
+	return e.add(slog.Attr{Key: key, Value: slog.GroupValue(dict.attrs...)})
+}
+
+// Msg sends the event with msg
+func (e *Event) Msg(msg string) {
+	if e == nil {
+		return
+	}
+	e.write(msg)
+}
+
+// Msgf sends the event with a formatted message
+func (e *Event) Msgf(format string, args ...any) {
+	if e == nil {
+		return
+	}
+	e.write(fmt.Sprintf(format, args...))
+}
+
+// Send sends the event with an empty message
+func (e *Event) Send() {
+	if e == nil {
+		return
+	}
+	e.write("")
+}
+
+// add appends attr unless the event is nil
+func (e *Event) add(attr slog.Attr) *Event {
+	if e == nil {
+		return nil
+	}
+	e.attrs = append(e.attrs, attr)
+	return e
+}
+
+// write handles the record, attributed to the caller of the sending method,
+// and exits or panics for fatal and panic events. It must be called
+// directly by that method.
+func (e *Event) write(msg string) {
+	ctx := e.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if e.enabled {
+		// Skip runtime.Callers, write and the sending method
+		var pcs [1]uintptr
+		runtime.Callers(3, pcs[:])
+
+		r := slog.NewRecord(time.Now(), e.level, msg, pcs[0])
+		r.AddAttrs(e.attrs...)
+		_ = e.logger.handler.Handle(ctx, r)
+	}
+
+	if e.level < logger.LevelPanic {
+		return
+	}
+	if e.logger.flusher != nil {
+		_ = e.logger.flusher.Flush(ctx)
+	}
+	if e.level >= logger.LevelFatal {
+		exit(1)
+		return
+	}
+	panic(msg)
+}
+
+// stringerAttr returns the String of val, or null for a nil val
+func stringerAttr(key string, val fmt.Stringer) slog.Attr {
+	if val == nil {
+		return slog.Any(key, nil)
+	}
+	return slog.String(key, val.String())
+}
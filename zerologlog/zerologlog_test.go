@@ -0,0 +1,141 @@
+package zerologlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/legrch/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decode(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var m map[string]any
+		require.NoError(t, dec.Decode(&m))
+		records = append(records, m)
+	}
+	return records
+}
+
+// flushCounter counts Flush calls
+type flushCounter struct{ n int }
+
+func (f *flushCounter) Flush(context.Context) error {
+	f.n++
+	return nil
+}
+
+func TestEvent(t *testing.T) {
+	var buf bytes.Buffer
+	zl := New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true}), nil)
+
+	zl.Info().
+		Str("addr", "localhost").
+		Int("pool", 4).
+		Bool("tls", true).
+		Dur("took", time.Second).
+		Strs("tags", []string{"a"}).
+		Dict("user", Dict().Str("name", "alice").Int("id", 7)).
+		Err(nil).
+		Msgf("connected in %s", "1s")
+
+	records := decode(t, &buf)
+	require.Len(t, records, 1)
+	r := records[0]
+	assert.Equal(t, "INFO", r["level"])
+	assert.Equal(t, "connected in 1s", r["msg"])
+	assert.Equal(t, "localhost", r["addr"])
+	assert.InDelta(t, 4, r["pool"], 0)
+	assert.Equal(t, true, r["tls"])
+	assert.Equal(t, []any{"a"}, r["tags"])
+	assert.Equal(t, map[string]any{"name": "alice", "id": float64(7)}, r["user"])
+	assert.NotContains(t, r, logger.ErrorKey)
+
+	source, ok := r["source"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "github.com/legrch/logger/zerologlog.TestEvent", source["function"])
+}
+
+func TestDisabledEvent(t *testing.T) {
+	var buf bytes.Buffer
+	zl := New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}), nil)
+
+	e := zl.Debug()
+	assert.Nil(t, e)
+	assert.False(t, e.Enabled())
+	e.Str("k", "v").Int("n", 1).Msg("hidden")
+
+	zl.Warn().Discard().Msg("discarded")
+	zl.Err(errors.New("boom")).Send()
+
+	records := decode(t, &buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "ERROR", records[0]["level"])
+	assert.Equal(t, "boom", records[0][logger.ErrorKey].(map[string]any)[logger.ErrorMessageKey])
+
+	var zero Logger
+	assert.Nil(t, zero.Error())
+}
+
+func TestContext(t *testing.T) {
+	var buf bytes.Buffer
+	base := New(slog.NewJSONHandler(&buf, nil), nil).With().Str("service", "api")
+	a := base.Str("worker", "a").Logger()
+	b := base.Str("worker", "b").Logger()
+
+	a.Info().Msg("one")
+	b.Info().Msg("two")
+
+	records := decode(t, &buf)
+	require.Len(t, records, 2)
+	assert.Equal(t, "api", records[0]["service"])
+	assert.Equal(t, "a", records[0]["worker"])
+	assert.Equal(t, "b", records[1]["worker"])
+}
+
+func TestEventContext(t *testing.T) {
+	mock := &logger.MockLogger{}
+	zl := New(logger.NewContextHandler(mock), nil)
+
+	ctx := logger.ContextWithAttrs(context.Background(), slog.String("request_id", "r1"))
+	zl.Info().Ctx(ctx).Msg("handled")
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 1)
+	assert.Equal(t, []slog.Attr{slog.String("request_id", "r1")}, logs[0].Attrs)
+}
+
+func TestFatalAndPanic(t *testing.T) {
+	var codes []int
+	prev := exit
+	exit = func(c int) { codes = append(codes, c) }
+	t.Cleanup(func() { exit = prev })
+
+	flusher := &flushCounter{}
+	mock := &logger.MockLogger{}
+	zl := New(mock, &Options{Flusher: flusher})
+
+	zl.Fatal().Str("path", "/etc/app.yaml").Msg("config missing")
+	assert.Equal(t, []int{1}, codes)
+	assert.PanicsWithValue(t, "bad state", func() { zl.Panic().Msg("bad state") })
+	assert.Equal(t, 2, flusher.n)
+
+	logs := mock.GetLogs()
+	require.Len(t, logs, 2)
+	assert.Equal(t, logger.LevelFatal, logs[0].Level)
+	assert.Equal(t, logger.LevelPanic, logs[1].Level)
+
+	var zero Logger
+	zero.Fatal().Msg("still exits")
+	assert.Equal(t, []int{1, 1}, codes)
+}